@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -13,9 +17,17 @@ import (
 )
 
 var (
-	debug  bool
-	output string
-	utils  factory.Utils
+	debug     bool
+	output    string
+	retries   int
+	retryPost bool
+	rateLimit float64
+	rateBurst int
+	maxConc   int
+	insecure  bool
+	noCache   bool
+	strict    string
+	utils     factory.Utils
 )
 
 func main() {
@@ -27,9 +39,20 @@ func main() {
 	rootCmd := &cobra.Command{Use: "repoflow"}
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug logging")
 	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "Define output (text, yaml, json)")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", cfg.Retry.MaxAttempts, "Maximum attempts per request (1 disables retries)")
+	rootCmd.PersistentFlags().BoolVar(&retryPost, "retry-post", cfg.Retry.RetryNonIdempotent, "Also retry non-idempotent requests (POST)")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", cfg.RateLimit, "Maximum requests per second (0 for unlimited)")
+	rootCmd.PersistentFlags().IntVar(&rateBurst, "rate-burst", cfg.RateBurst, "Maximum burst of requests allowed by --rate-limit")
+	rootCmd.PersistentFlags().IntVar(&maxConc, "max-concurrency", cfg.MaxConcurrency, "Maximum in-flight requests (0 for unlimited)")
+	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", cfg.TLS.InsecureSkipVerify, "Skip TLS certificate verification (dangerous)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Disable the local response cache enabled by the configuration")
+	rootCmd.PersistentFlags().StringVar(&strict, "strict-decoding", cfg.StrictDecoding, "Compare responses with the Go types (off, warn, error)")
 	rootCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"text", "yaml", "json"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	rootCmd.RegisterFlagCompletionFunc("strict-decoding", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"off", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		level := slog.LevelInfo
@@ -41,6 +64,35 @@ func main() {
 		logger := slog.New(handler)
 		slog.SetDefault(logger)
 		utils.Logger = logger
+
+		// The flags default to the main configuration, only the ones set
+		// explicitly override the profiles loaded later
+		flags := cmd.Flags()
+		utils.Overrides = func(c *config.Config) {
+			if flags.Changed("retries") {
+				c.Retry.MaxAttempts = retries
+			}
+			if flags.Changed("retry-post") {
+				c.Retry.RetryNonIdempotent = retryPost
+			}
+			if flags.Changed("rate-limit") {
+				c.RateLimit = rateLimit
+			}
+			if flags.Changed("rate-burst") {
+				c.RateBurst = rateBurst
+			}
+			if flags.Changed("max-concurrency") {
+				c.MaxConcurrency = maxConc
+			}
+			if flags.Changed("insecure") {
+				c.TLS.InsecureSkipVerify = insecure
+			}
+			if flags.Changed("strict-decoding") {
+				c.StrictDecoding = strict
+			}
+			c.Cache.Enabled = c.Cache.Enabled && !noCache
+		}
+		utils.Overrides(cfg)
 		utils.Cfg = cfg
 		utils.Output = output
 
@@ -49,9 +101,38 @@ func main() {
 
 	rootCmd.AddCommand(cli.WorkspaceCmd(&utils))
 	rootCmd.AddCommand(cli.RepositoryCmd(&utils))
+	rootCmd.AddCommand(cli.CacheCmd(&utils))
+	rootCmd.AddCommand(cli.DownloadCmd(&utils))
+	rootCmd.AddCommand(cli.UploadCmd(&utils))
+	rootCmd.AddCommand(cli.StatusCmd(&utils))
+	rootCmd.AddCommand(cli.APICheckCmd(&utils))
+	rootCmd.AddCommand(cli.APICmd(&utils))
+
+	// Cancel in-flight requests on Ctrl-C or termination, the cause tells
+	// the signals apart in the exit code
+	ctx, cancel := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if sig, ok := <-signals; ok {
+			if sig == syscall.SIGTERM {
+				cancel(cli.ErrTerminated)
+			} else {
+				cancel(cli.ErrInterrupted)
+			}
+		}
+	}()
+	err = rootCmd.ExecuteContext(ctx)
+	signal.Stop(signals)
+	close(signals)
 
-	if err := rootCmd.Execute(); err != nil {
+	if cause := context.Cause(ctx); cause != nil && errors.Is(err, context.Canceled) {
+		err = fmt.Errorf("%w: %w", cause, err)
+	}
+	cancel(nil)
+
+	if err != nil {
 		slog.Debug("Error", "error", err)
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// APIManager handles the state and configuration for the api command
+type APIManager struct {
+	*factory.Utils
+	fields      []string
+	typedFields []string
+	input       string
+	paginate    bool
+	itemsField  string
+	raw         bool
+}
+
+// APICmd initializes the api command
+func APICmd(u *factory.Utils) *cobra.Command {
+	m := &APIManager{Utils: u}
+
+	var apiCmd = &cobra.Command{
+		Use:   "api [method] [path]",
+		Short: "Send an authenticated request to the RepoFlow API",
+		Long: `Send an authenticated request to the RepoFlow API, the path being relative to the API url.
+
+Fields are sent as query parameters for GET and DELETE requests, as a JSON object otherwise,
+or as query parameters when the body is given with --input.`,
+		Example: `  repoflow api GET /1/workspaces
+  repoflow api POST /1/workspaces -f name=team -F packageLimit=100
+  repoflow api GET /1/workspaces/ws-1/repositories/repo-1/packages --paginate`,
+		Args:         cobra.ExactArgs(2),
+		RunE:         m.api,
+		SilenceUsage: true,
+	}
+
+	apiCmd.Flags().StringArrayVarP(&m.fields, "raw-field", "f", nil, "Add a string field key=value, can be repeated")
+	apiCmd.Flags().StringArrayVarP(&m.typedFields, "field", "F", nil, "Add a typed field key=value (true, false, null, numbers, @file), can be repeated")
+	apiCmd.Flags().StringVar(&m.input, "input", "", "File holding the JSON body, - for stdin")
+	apiCmd.Flags().BoolVar(&m.paginate, "paginate", false, "Fetch all pages of a paginated GET endpoint")
+	apiCmd.Flags().StringVar(
+		&m.itemsField, "items-field", "", "Field holding the items of the pages (--paginate), packages or items by default",
+	)
+	apiCmd.Flags().BoolVar(&m.raw, "raw", false, "Print the response body as is")
+
+	return apiCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *APIManager) api(cmd *cobra.Command, args []string) error {
+	method := strings.ToUpper(args[0])
+	path := args[1]
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	if m.paginate && method != http.MethodGet {
+		return fmt.Errorf("--paginate requires a GET request")
+	}
+	if m.itemsField != "" && !m.paginate {
+		return fmt.Errorf("--items-field requires --paginate")
+	}
+
+	fields, err := m.parseFields()
+	if err != nil {
+		return err
+	}
+
+	var body any
+	switch {
+	case m.input != "":
+		data, err := readInput(m.input)
+		if err != nil {
+			return err
+		}
+		if !json.Valid(data) {
+			return fmt.Errorf("%s is not a valid JSON document", m.input)
+		}
+		body = json.RawMessage(data)
+		path, err = withQuery(path, fields)
+		if err != nil {
+			return err
+		}
+	case method == http.MethodGet || method == http.MethodDelete:
+		if path, err = withQuery(path, fields); err != nil {
+			return err
+		}
+	case len(fields) > 0:
+		body = fields
+	}
+
+	svc, err := m.GetRequestService()
+	if err != nil {
+		return err
+	}
+
+	var data json.RawMessage
+	if m.paginate {
+		data, err = paginate(cmd.Context(), svc, path, m.itemsField)
+	} else {
+		err = svc.DoRequest(cmd.Context(), method, path, body, &data)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+	if m.raw {
+		_, err := os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	if m.Output == "text" || m.Output == "" {
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return err
+		}
+		fmt.Println(out.String())
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return factory.HandleOutput(m.Utils, value)
+}
+
+// parseFields returns the fields given by -f and -F
+func (m *APIManager) parseFields() (map[string]any, error) {
+	fields := map[string]any{}
+	for _, field := range m.fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q, expected key=value", field)
+		}
+		fields[key] = value
+	}
+	for _, field := range m.typedFields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q, expected key=value", field)
+		}
+		typed, err := typedValue(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = typed
+	}
+	return fields, nil
+}
+
+// typedValue converts a -F value to its JSON type, @file reads the file
+func typedValue(value string) (any, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, nil
+	}
+	if file, ok := strings.CutPrefix(value, "@"); ok {
+		data, err := readInput(file)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	return value, nil
+}
+
+// readInput reads a file, - for stdin
+func readInput(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(file)
+}
+
+// withQuery adds the fields to the query string of path
+func withQuery(path string, fields map[string]any) (string, error) {
+	if len(fields) == 0 {
+		return path, nil
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for key, value := range fields {
+		if value == nil {
+			value = ""
+		}
+		query.Set(key, fmt.Sprint(value))
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// paginatedItemsFields are the fields holding the items of the paginated
+// endpoints
+var paginatedItemsFields = []string{"packages", "items"}
+
+// paginate follows the offset pagination of path and returns the items of
+// every page as a single JSON array. The items are read from itemsField, or
+// from the first of paginatedItemsFields present in the page. Responses
+// which are not paginated objects are returned as is.
+func paginate(ctx context.Context, svc repoflow.RequestService, path string, itemsField string) (json.RawMessage, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if query.Get("limit") == "" {
+		query.Set("limit", strconv.Itoa(repoflow.DefaultPageSize))
+	}
+
+	items := []json.RawMessage{}
+	for {
+		query.Set("offset", strconv.Itoa(offset))
+		u.RawQuery = query.Encode()
+
+		var data json.RawMessage
+		if err := svc.DoRequest(ctx, http.MethodGet, u.String(), nil, &data); err != nil {
+			return nil, err
+		}
+
+		var page map[string]json.RawMessage
+		if json.Unmarshal(data, &page) != nil {
+			return data, nil
+		}
+		var total int
+		if json.Unmarshal(page["total"], &total) != nil {
+			return data, nil
+		}
+
+		field := itemsField
+		if field == "" {
+			field = pageItemsField(page)
+		}
+		var pageItems []json.RawMessage
+		if err := json.Unmarshal(page[field], &pageItems); err != nil || page[field] == nil {
+			return nil, fmt.Errorf("page of %s has no %q array, set the items field with --items-field", path, field)
+		}
+		items = append(items, pageItems...)
+
+		offset += len(pageItems)
+		if len(pageItems) == 0 || offset >= total {
+			break
+		}
+	}
+
+	return json.Marshal(items)
+}
+
+// pageItemsField returns the first of paginatedItemsFields present in page
+func pageItemsField(page map[string]json.RawMessage) string {
+	for _, field := range paginatedItemsFields {
+		if _, ok := page[field]; ok {
+			return field
+		}
+	}
+	return paginatedItemsFields[0]
+}
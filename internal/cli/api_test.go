@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+// pagedService serves three items two by two under field, next to another
+// array so the items cannot be guessed
+func pagedService(field string) *repoflowtest.FakeRequestService {
+	return &repoflowtest.FakeRequestService{
+		DoRequestFunc: func(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+			u, _ := url.Parse(path)
+			offset, _ := strconv.Atoi(u.Query().Get("offset"))
+			ids := []string{`{"id":"a"}`, `{"id":"b"}`, `{"id":"c"}`}[offset:min(offset+2, 3)]
+			page := fmt.Sprintf(`{"total":3,"tags":["x","y","z"],%q:[%s]}`, field, strings.Join(ids, ","))
+			return json.Unmarshal([]byte(page), result)
+		},
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name       string
+		field      string
+		itemsField string
+		wantErr    bool
+	}{
+		{"packages", "packages", "", false},
+		{"items", "items", "", false},
+		{"explicit field", "results", "results", false},
+		{"unknown field", "results", "", true},
+		{"wrong explicit field", "packages", "tags2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := paginate(context.Background(), pagedService(tt.field), "/1/list?limit=2", tt.itemsField)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "--items-field") {
+					t.Errorf("error = %v, want a hint about --items-field", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(data); got != `[{"id":"a"},{"id":"b"},{"id":"c"}]` {
+				t.Errorf("items = %s", got)
+			}
+		})
+	}
+}
+
+func TestPaginateNotPaginated(t *testing.T) {
+	svc := &repoflowtest.FakeRequestService{
+		DoRequestFunc: func(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+			return json.Unmarshal([]byte(`[{"id":"ws-1"}]`), result)
+		},
+	}
+	data, err := paginate(context.Background(), svc, "/1/workspaces", "")
+	if err != nil || string(data) != `[{"id":"ws-1"}]` {
+		t.Errorf("paginate() = %s, %v, want the response as is", data, err)
+	}
+}
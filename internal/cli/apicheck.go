@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// APICheckManager handles the state and configuration for the api-check command
+type APICheckManager struct {
+	*factory.Utils
+	workspace string
+	results   []apiCheckResult
+}
+
+// apiCheckResult is the comparison of an endpoint response with its Go type
+type apiCheckResult struct {
+	Endpoint string   `json:"endpoint" yaml:"endpoint"`
+	Type     string   `json:"type" yaml:"type"`
+	Status   string   `json:"status" yaml:"status"`
+	Unknown  []string `json:"unknown,omitempty" yaml:"unknown,omitempty"`
+	Missing  []string `json:"missing,omitempty" yaml:"missing,omitempty"`
+	Error    string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// apiCheckRow is the text output of a result
+type apiCheckRow struct {
+	Endpoint string
+	Type     string
+	Status   string
+	Details  string
+}
+
+// APICheckCmd initializes the api-check command
+func APICheckCmd(u *factory.Utils) *cobra.Command {
+	m := &APICheckManager{Utils: u}
+
+	var apiCheckCmd = &cobra.Command{
+		Use:          "api-check",
+		Short:        "Compare the responses of the read endpoints with the Go types",
+		Args:         cobra.NoArgs,
+		RunE:         m.apiCheck,
+		SilenceUsage: true,
+	}
+
+	apiCheckCmd.Flags().StringVarP(
+		&m.workspace, "workspace", "w", "", "Workspace to inspect (id or name), the first one by default",
+	)
+
+	return apiCheckCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *APICheckManager) apiCheck(cmd *cobra.Command, args []string) error {
+	svc, err := m.GetRequestService()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	var workspaces []repoflow.Workspaces
+	if !m.check(ctx, svc, repoflow.WorkspacesEndpoint, &workspaces) {
+		workspaces = nil
+	}
+
+	wsID := ""
+	switch {
+	case m.workspace != "":
+		if wsID, err = m.ResolveWorkspace(ctx, m.workspace); err != nil {
+			return err
+		}
+	case len(workspaces) > 0:
+		wsID = workspaces[0].Id
+	}
+
+	if wsID != "" {
+		wsPath := fmt.Sprintf("%s/%s", repoflow.WorkspacesEndpoint, wsID)
+		m.check(ctx, svc, wsPath, &repoflow.Workspace{})
+
+		var repositories []repoflow.Repositories
+		if !m.check(ctx, svc, wsPath+repoflow.RepositoryEndpoint, &repositories) {
+			repositories = nil
+		}
+
+		// The fields depend on the repository type, check one of each
+		seen := map[string]bool{}
+		for _, repo := range repositories {
+			if seen[repo.RepositoryType] {
+				continue
+			}
+			seen[repo.RepositoryType] = true
+			m.check(ctx, svc, fmt.Sprintf("%s%s/%s", wsPath, repoflow.RepositoryEndpoint, repo.Id), &repoflow.Repository{})
+		}
+		if len(repositories) > 0 {
+			path := fmt.Sprintf("%s%s/%s/packages?limit=1", wsPath, repoflow.RepositoryEndpoint, repositories[0].Id)
+			m.check(ctx, svc, path, &repoflow.RepositoryPackages{})
+		}
+	}
+
+	m.check(ctx, svc, repoflow.CurrentUserEndpoint, &repoflow.Identity{})
+
+	if m.Output == "text" || m.Output == "" {
+		rows := make([]apiCheckRow, len(m.results))
+		for i, result := range m.results {
+			rows[i] = apiCheckRow{Endpoint: result.Endpoint, Type: result.Type, Status: result.Status, Details: result.Error}
+			if result.Status == "drift" {
+				diff := repoflow.SchemaDiff{Unknown: result.Unknown, Missing: result.Missing}
+				rows[i].Details = diff.String()
+			}
+		}
+		if err := m.TableFormat(os.Stdout, rows); err != nil {
+			return err
+		}
+	} else if err := factory.HandleOutput(m.Utils, m.results); err != nil {
+		return err
+	}
+
+	drifts, failures := 0, 0
+	for _, result := range m.results {
+		switch result.Status {
+		case "drift":
+			drifts++
+		case "failed":
+			failures++
+		}
+	}
+	if drifts > 0 {
+		return fmt.Errorf("%w on %d endpoint(s)", repoflow.ErrSchemaMismatch, drifts)
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d endpoint(s) failed", failures)
+	}
+	return nil
+}
+
+// check fetches path, compares the response with the type v points to and
+// decodes it into v. It reports whether the response was decoded.
+func (m *APICheckManager) check(ctx context.Context, svc repoflow.RequestService, path string, v any) bool {
+	result := apiCheckResult{
+		Endpoint: "GET " + strings.SplitN(path, "?", 2)[0],
+		Type:     reflect.TypeOf(v).Elem().String(),
+	}
+
+	var raw json.RawMessage
+	err := svc.DoRequest(ctx, http.MethodGet, path, nil, &raw)
+	switch {
+	case errors.Is(err, repoflow.ErrNotFound) && path == repoflow.CurrentUserEndpoint:
+		result.Status = "unavailable"
+	case err != nil:
+		result.Status = "failed"
+		result.Error = err.Error()
+	default:
+		diff, err := repoflow.CompareSchema(raw, v)
+		if err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+			break
+		}
+		if err := json.Unmarshal(raw, v); err != nil {
+			result.Status = "failed"
+			result.Error = fmt.Sprintf("failed to decode response: %v", err)
+			break
+		}
+		result.Status = "ok"
+		if !diff.Empty() {
+			result.Status = "drift"
+			result.Unknown = diff.Unknown
+			result.Missing = diff.Missing
+		}
+	}
+
+	m.results = append(m.results, result)
+	return result.Status == "ok" || result.Status == "drift"
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+func TestAPICheckRecordsDecodeErrors(t *testing.T) {
+	responses := map[string]string{
+		// The ID is a number, the list cannot be decoded
+		repoflow.WorkspacesEndpoint:  `[{"id":1,"name":"team"}]`,
+		repoflow.CurrentUserEndpoint: `{"id":"user-1","username":"admin","email":"a@b.c"}`,
+	}
+	u := newFakeUtils("json")
+	u.Requests = &repoflowtest.FakeRequestService{
+		DoRequestFunc: func(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+			data, ok := responses[path]
+			if !ok {
+				t.Errorf("unexpected request %s %s", method, path)
+				return repoflow.ErrNotFound
+			}
+			return json.Unmarshal([]byte(data), result)
+		},
+	}
+
+	out, err := execute(t, APICheckCmd(u))
+	if err == nil || !strings.Contains(err.Error(), "1 endpoint(s) failed") {
+		t.Errorf("error = %v, want one failed endpoint", err)
+	}
+	var results []apiCheckResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want the workspaces and the user", results)
+	}
+	if results[0].Status != "failed" || !strings.Contains(results[0].Error, "failed to decode response") {
+		t.Errorf("workspaces result = %+v", results[0])
+	}
+	if results[1].Status != "ok" {
+		t.Errorf("user result = %+v", results[1])
+	}
+}
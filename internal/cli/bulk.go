@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// bulkFlags holds the flags of the commands accepting several items
+type bulkFlags struct {
+	fromFile    string
+	concurrency int
+	failFast    bool
+}
+
+// bulkRow is the status of an item in the bulk output
+type bulkRow struct {
+	Item     string `json:"item" yaml:"item"`
+	Status   string `json:"status" yaml:"status"`
+	Duration string `json:"duration" yaml:"duration"`
+	Error    string `json:"error" yaml:"error"`
+}
+
+// bulkOutput is the structured bulk output
+type bulkOutput struct {
+	Results []bulkResult         `json:"results" yaml:"results"`
+	Summary repoflow.BulkSummary `json:"summary" yaml:"summary"`
+}
+
+type bulkResult struct {
+	bulkRow `yaml:",inline"`
+	Data    any `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// register adds the bulk flags to cmd
+func (f *bulkFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.fromFile, "from-file", "", "Read the items from a file, one per line, - for stdin")
+	cmd.Flags().IntVar(&f.concurrency, "concurrency", repoflow.DefaultBulkConcurrency, "Number of items processed at once")
+	cmd.Flags().BoolVar(&f.failFast, "fail-fast", false, "Stop at the first error")
+}
+
+// items returns the items given as arguments followed by the ones read
+// from --from-file, blank lines and lines starting with # are ignored
+func (f *bulkFlags) items(args []string) ([]string, error) {
+	items := append([]string{}, args...)
+
+	if f.fromFile != "" {
+		var r io.Reader = os.Stdin
+		if f.fromFile != "-" {
+			file, err := os.Open(f.fromFile)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			r = file
+		}
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			items = append(items, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.fromFile, err)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("requires at least one item, as argument or with --from-file")
+	}
+	return items, nil
+}
+
+// single reports whether the command runs on a single argument, keeping
+// the plain output of the command
+func (f *bulkFlags) single(items []string) bool {
+	return len(items) == 1 && f.fromFile == ""
+}
+
+// runBulk runs fn on the items and prints a status per item
+func runBulk[T any](ctx context.Context, u *factory.Utils, f *bulkFlags, items []string, fn func(ctx context.Context, item string) (T, error)) error {
+	// The resolver is shared by the workers, build it beforehand
+	if _, err := u.GetResolver(); err != nil {
+		return err
+	}
+
+	report := repoflow.Bulk(ctx, items, repoflow.BulkOptions{
+		Concurrency: f.concurrency,
+		FailFast:    f.failFast,
+	}, fn)
+
+	out := bulkOutput{Summary: report.Summary}
+	out.Summary.Duration = out.Summary.Duration.Round(time.Millisecond)
+	for _, result := range report.Results {
+		row := bulkResult{bulkRow: bulkRow{Item: result.Item, Status: "ok"}}
+		switch {
+		case result.Skipped:
+			row.Status = "skipped"
+		case result.Err != nil:
+			row.Status = "failed"
+			row.Error = result.Err.Error()
+		default:
+			row.Data = result.Value
+		}
+		if !result.Skipped {
+			row.Duration = result.Duration.Round(time.Millisecond).String()
+		}
+		out.Results = append(out.Results, row)
+	}
+
+	if u.Output == "text" || u.Output == "" {
+		rows := make([]bulkRow, len(out.Results))
+		for i, result := range out.Results {
+			rows[i] = result.bulkRow
+		}
+		if err := u.TableFormat(os.Stdout, rows); err != nil {
+			return err
+		}
+		s := report.Summary
+		fmt.Printf("\n%d succeeded, %d failed, %d skipped in %s\n", s.Succeeded, s.Failed, s.Skipped, s.Duration.Round(time.Millisecond))
+	} else if err := factory.HandleOutput(u, out); err != nil {
+		return err
+	}
+
+	if err := report.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
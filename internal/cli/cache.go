@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+)
+
+// CacheManager handles the state and configuration for cache commands
+type CacheManager struct {
+	*factory.Utils
+}
+
+// CacheCmd initializes the parent command and its subcommands
+func CacheCmd(u *factory.Utils) *cobra.Command {
+	m := &CacheManager{Utils: u}
+
+	// Main cache command
+	var cacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local response cache",
+	}
+
+	// Clear sub-command
+	var clearCmd = &cobra.Command{
+		Use:          "clear",
+		Short:        "Remove all cached responses",
+		Args:         cobra.NoArgs,
+		RunE:         m.cacheClear,
+		SilenceUsage: true,
+	}
+
+	// Register sub-commands
+	cacheCmd.AddCommand(clearCmd)
+
+	return cacheCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *CacheManager) cacheClear(cmd *cobra.Command, args []string) error {
+	cache, err := factory.GetCache(m.Cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := cache.Clear(); err != nil {
+		return err
+	}
+
+	if m.Output == "text" || m.Output == "" {
+		fmt.Printf("Successfully cleared cache '%s'\n", cache.Dir)
+		return nil
+	}
+
+	return factory.HandleOutput(m.Utils, map[string]string{"dir": cache.Dir})
+}
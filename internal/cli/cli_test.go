@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/config"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+// newTestServer starts a fake server and returns the utils of a CLI
+// invocation pointing at it
+func newTestServer(t *testing.T, output string) (*repoflowtest.Server, *factory.Utils) {
+	t.Helper()
+	srv := repoflowtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	u := &factory.Utils{
+		Cfg:           &config.Config{URL: srv.URL},
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Output:        output,
+		ClientOptions: []repoflow.Option{repoflow.WithRetryPolicy(nil)},
+	}
+	return srv, u
+}
+
+// execute runs cmd with args and returns what it printed on stdout
+func execute(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err = cmd.Execute()
+	w.Close()
+	return <-out, err
+}
+
+func TestWorkspaceCommands(t *testing.T) {
+	srv, u := newTestServer(t, "json")
+	srv.AddWorkspace("existing")
+
+	out, err := execute(t, WorkspaceCmd(u), "create", "team", "--package-limit", "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ws repoflow.Workspace
+	if err := json.Unmarshal([]byte(out), &ws); err != nil {
+		t.Fatalf("create output %q: %v", out, err)
+	}
+	if ws.Name != "team" || ws.PackageLimit == nil || *ws.PackageLimit != 10 {
+		t.Errorf("created workspace = %+v", ws)
+	}
+
+	out, err = execute(t, WorkspaceCmd(u), "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list []repoflow.Workspaces
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("list output %q: %v", out, err)
+	}
+	if len(list) != 2 {
+		t.Errorf("workspaces = %+v", list)
+	}
+
+	_, err = execute(t, WorkspaceCmd(u), "create", "team")
+	if ExitCode(err) != ExitConflict {
+		t.Errorf("duplicate create exit code = %d (%v), want %d", ExitCode(err), err, ExitConflict)
+	}
+}
+
+func TestRepositoryCommands(t *testing.T) {
+	srv, u := newTestServer(t, "text")
+	ws := srv.AddWorkspace("team")
+
+	out, err := execute(t, RepositoryCmd(u), "create", "local", "npm-local", "-t", "npm", "-w", "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "npm-local") {
+		t.Errorf("create output = %q", out)
+	}
+	if _, err := execute(t, RepositoryCmd(u), "create", "virtual", "npm", "-t", "npm", "-w", "team",
+		"--child-repository", "npm-local"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = execute(t, RepositoryCmd(u), "list", "-w", ws.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"npm-local", "virtual"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("list output misses %q:\n%s", name, out)
+		}
+	}
+
+	_, err = execute(t, RepositoryCmd(u), "get", "missing", "-w", "team")
+	if !errors.Is(err, repoflow.ErrNotFound) || ExitCode(err) != ExitNotFound {
+		t.Errorf("get missing error = %v, want not found", err)
+	}
+}
+
+func TestVirtualCommands(t *testing.T) {
+	srv, u := newTestServer(t, "json")
+	ws := srv.AddWorkspace("team")
+	first := srv.AddRepository(ws.Id, repoflow.Repository{Name: "first", PackageType: "npm", RepositoryType: "local"})
+	second := srv.AddRepository(ws.Id, repoflow.Repository{Name: "second", PackageType: "npm", RepositoryType: "local"})
+	srv.AddRepository(ws.Id, repoflow.Repository{Name: "maven", PackageType: "maven", RepositoryType: "local"})
+	client := srv.Client()
+	virtual, err := client.CreateVirtualRepository(t.Context(), ws.Id, repoflow.RepositoryVirtualOptions{
+		Name: "npm", PackageType: "npm", ChildRepositoryIds: []string{first.Id},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := execute(t, RepositoryCmd(u), "virtual", "add-child", "npm", "second", "-w", "team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execute(t, RepositoryCmd(u), "virtual", "set-upload-target", "npm", "second", "-w", "team"); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := client.GetRepository(t.Context(), ws.Id, virtual.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.ChildRepositories) != 2 || repo.UploadTargetLocalRepository.Id != second.Id {
+		t.Errorf("virtual repository = %+v", repo)
+	}
+
+	_, err = execute(t, RepositoryCmd(u), "virtual", "add-child", "npm", "maven", "-w", "team")
+	if !errors.Is(err, repoflow.ErrInvalidMember) {
+		t.Errorf("add maven child error = %v, want ErrInvalidMember", err)
+	}
+}
+
+func TestCloneCommand(t *testing.T) {
+	srv, u := newTestServer(t, "json")
+	src := srv.AddWorkspace("src")
+	srv.AddWorkspace("dst")
+	url, username := "https://registry.npmjs.org", "bot"
+	srv.AddRepository(src.Id, repoflow.Repository{
+		Name:                     "npm-proxy",
+		PackageType:              "npm",
+		RepositoryType:           "remote",
+		RemoteRepositoryUrl:      &url,
+		RemoteRepositoryUsername: &username,
+	})
+	t.Setenv("NPM_PASSWORD", "hunter2")
+
+	// Without terminal to prompt on, the password must be given
+	r, w, _ := os.Pipe()
+	defer r.Close()
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	_, err := execute(t, RepositoryCmd(u), "clone", "npm-proxy", "npm-copy", "-w", "src", "--to-workspace", "dst")
+	os.Stdin = stdin
+	if err == nil || !strings.Contains(err.Error(), "--remote-password-env") {
+		t.Errorf("clone without password error = %v, want a hint about the password flags", err)
+	}
+
+	out, err := execute(t, RepositoryCmd(u), "clone", "npm-proxy", "npm-copy", "-w", "src",
+		"--to-workspace", "dst", "--remote-password-env", "NPM_PASSWORD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var repo repoflow.Repository
+	if err := json.Unmarshal([]byte(out), &repo); err != nil {
+		t.Fatalf("clone output %q: %v", out, err)
+	}
+	if repo.Name != "npm-copy" || repo.RepositoryType != "remote" || repo.RemoteRepositoryUrl == nil || *repo.RemoteRepositoryUrl != url {
+		t.Errorf("clone = %+v", repo)
+	}
+
+	var sent bool
+	for _, req := range srv.Requests() {
+		if req.Method == "POST" && strings.Contains(string(req.Body), `"remoteRepositoryPassword":"hunter2"`) {
+			sent = true
+		}
+	}
+	if !sent {
+		t.Error("password not sent with the clone")
+	}
+}
+
+func TestCloneToProfileRequiresWorkspace(t *testing.T) {
+	srv, u := newTestServer(t, "json")
+	srv.AddWorkspace("src")
+
+	_, err := execute(t, RepositoryCmd(u), "clone", "npm", "npm-copy", "-w", "src", "--to-profile", "staging")
+	if err == nil || !strings.Contains(err.Error(), "--to-workspace") {
+		t.Errorf("clone error = %v, want --to-workspace to be required", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("requests sent = %d, want none", n)
+	}
+}
+
+func TestCommandsHonorContext(t *testing.T) {
+	srv, u := newTestServer(t, "json")
+	srv.AddWorkspace("team")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrInterrupted)
+	cmd := WorkspaceCmd(u)
+	cmd.SetContext(ctx)
+	_, err := execute(t, cmd, "list")
+	if ExitCode(err) != ExitCanceled {
+		t.Errorf("exit code = %d (%v), want %d", ExitCode(err), err, ExitCanceled)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("requests sent after the cancellation = %d", n)
+	}
+}
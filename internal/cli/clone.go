@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// cloneFlags holds the flags of the clone command
+type cloneFlags struct {
+	toWorkspace   string
+	toProfile     string
+	username      string
+	password      string
+	passwordEnv   string
+	passwordStdin bool
+}
+
+// cloneCmd initializes the command copying a repository definition
+func cloneCmd(m *RepositoryManager) *cobra.Command {
+	var cloneCmd = &cobra.Command{
+		Use:   "clone [source] [destination]",
+		Short: "Create a repository with the definition of another one (ID or name)",
+		Long: `Create a repository with the store type and settings of another one, packages are not copied.
+
+The children and the upload target of a virtual repository are mapped by name to the repositories
+of the destination workspace. The API never returns the password of a remote repository: it is
+read from --remote-password, --remote-password-env or --remote-password-stdin, or prompted when
+the remote repository has a username. Without terminal, a repository with a username requires one
+of these flags.`,
+		Example: `  repoflow repository clone npm-proxy npm-proxy-copy -w team
+  repoflow repository clone npm-all npm-all -w team --to-workspace new-team
+  repoflow repository clone npm-proxy npm-proxy -w team --to-profile staging --to-workspace team --remote-password-env NPM_PASSWORD`,
+		Args:         cobra.ExactArgs(2),
+		RunE:         m.repositoryClone,
+		SilenceUsage: true,
+	}
+
+	cloneCmd.Flags().StringVar(
+		&m.clone.toWorkspace, "to-workspace", "", "Destination workspace (id or name), the source workspace by default, required with --to-profile",
+	)
+	cloneCmd.Flags().StringVar(
+		&m.clone.toProfile, "to-profile", "", "Configuration profile of the destination instance, the current one by default",
+	)
+	cloneCmd.Flags().StringVar(&m.clone.username, "remote-username", "", "Username of the remote repository, the source one by default")
+	cloneCmd.Flags().StringVar(&m.clone.password, "remote-password", "", "Password for the remote repository")
+	cloneCmd.Flags().StringVar(&m.clone.passwordEnv, "remote-password-env", "", "Environment variable holding the remote repository password")
+	cloneCmd.Flags().BoolVar(&m.clone.passwordStdin, "remote-password-stdin", false, "Read the remote repository password from stdin")
+	cloneCmd.MarkFlagsMutuallyExclusive("remote-password", "remote-password-env", "remote-password-stdin")
+
+	return cloneCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *RepositoryManager) repositoryClone(cmd *cobra.Command, args []string) error {
+	// The source workspace means nothing on another instance
+	toWorkspace := m.clone.toWorkspace
+	if toWorkspace == "" {
+		if m.clone.toProfile != "" {
+			return fmt.Errorf("--to-workspace is required with --to-profile")
+		}
+		toWorkspace = m.workspace
+	}
+
+	srcWsID, srcID, err := m.ResolveRepository(cmd.Context(), m.workspace, args[0])
+	if err != nil {
+		return err
+	}
+
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+	src, err := svc.GetRepository(cmd.Context(), srcWsID, srcID)
+	if err != nil {
+		return err
+	}
+
+	dst := m.Utils
+	if m.clone.toProfile != "" {
+		if dst, err = m.Profile(m.clone.toProfile); err != nil {
+			return err
+		}
+	}
+	dstWsID, err := dst.ResolveWorkspace(cmd.Context(), toWorkspace)
+	if err != nil {
+		return err
+	}
+
+	opts := repoflow.CloneOptions{Name: args[1]}
+	if src.RepositoryType == "remote" {
+		if cmd.Flags().Changed("remote-username") {
+			opts.RemoteRepositoryUsername = &m.clone.username
+		}
+		if opts.RemoteRepositoryPassword, err = m.clonePassword(src, opts.RemoteRepositoryUsername); err != nil {
+			return err
+		}
+	}
+
+	dstSvc, err := dst.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	data, err := repoflow.NewCloner(dstSvc).Clone(cmd.Context(), src, dstWsID, opts)
+	if err != nil {
+		return err
+	}
+
+	if m.Output == "text" || m.Output == "" {
+		fmt.Printf("Successfully cloned repository '%s' to '%s' on workspace '%s'.\n", args[0], args[1], toWorkspace)
+		return nil
+	}
+
+	return factory.HandleOutput(m.Utils, data)
+}
+
+// clonePassword returns the password of the remote repository src, from the
+// flags or prompted when the repository has a username. Without terminal to
+// prompt on, a repository with a username requires a password flag.
+func (m *RepositoryManager) clonePassword(src *repoflow.Repository, username *string) (string, error) {
+	switch {
+	case m.clone.password != "":
+		return m.clone.password, nil
+	case m.clone.passwordEnv != "":
+		password, ok := os.LookupEnv(m.clone.passwordEnv)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", m.clone.passwordEnv)
+		}
+		return password, nil
+	case m.clone.passwordStdin:
+		password, err := readLine(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+		return password, nil
+	}
+
+	if username == nil {
+		username = src.RemoteRepositoryUsername
+	}
+	if username == nil || *username == "" {
+		return "", nil
+	}
+	if !isTerminal(os.Stdin) {
+		return "", fmt.Errorf("remote repository %s has the username %s, set its password with --remote-password-env or --remote-password-stdin",
+			src.Name, *username)
+	}
+	return promptSecret(fmt.Sprintf("Password of %s for %s: ", *username, src.Name))
+}
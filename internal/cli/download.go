@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// DownloadManager handles the state and configuration for the download command
+type DownloadManager struct {
+	*factory.Utils
+	file   string
+	resume bool
+	sha256 string
+	verify bool
+}
+
+// DownloadCmd initializes the download command
+func DownloadCmd(u *factory.Utils) *cobra.Command {
+	m := &DownloadManager{Utils: u}
+
+	var downloadCmd = &cobra.Command{
+		Use:          "download [path]",
+		Short:        "Download a file (path relative to the API url or absolute url)",
+		Args:         cobra.ExactArgs(1),
+		RunE:         m.download,
+		SilenceUsage: true,
+	}
+
+	downloadCmd.Flags().StringVarP(&m.file, "file", "f", "-", "Destination file, - for stdout")
+	downloadCmd.Flags().BoolVarP(&m.resume, "resume", "c", false, "Resume a partially downloaded file")
+	downloadCmd.Flags().StringVar(&m.sha256, "sha256", "", "Expected SHA-256 of the file")
+	downloadCmd.Flags().BoolVar(&m.verify, "verify", false, "Verify the file against the digest sent by the server")
+
+	return downloadCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *DownloadManager) download(cmd *cobra.Command, args []string) error {
+	svc, err := m.GetTransferService()
+	if err != nil {
+		return err
+	}
+
+	if m.file == "-" {
+		if m.resume {
+			return fmt.Errorf("--resume requires --file")
+		}
+		d, err := svc.Download(cmd.Context(), args[0], &repoflow.DownloadOptions{SHA256: m.sha256, Verify: m.verify})
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+
+		_, err = io.Copy(os.Stdout, d)
+		return err
+	}
+
+	// A resumed download completes the partial file in place, the others
+	// are written to a temporary file so a failure keeps the existing one
+	var partial *os.File
+	opts := &repoflow.DownloadOptions{SHA256: m.sha256, Verify: m.verify}
+	if m.resume {
+		partial, err = os.OpenFile(m.file, os.O_RDWR, 0)
+		switch {
+		case err == nil:
+			defer partial.Close()
+			if opts.Offset, opts.Hash, err = partialFile(partial); err != nil {
+				return err
+			}
+		case !errors.Is(err, fs.ErrNotExist):
+			return err
+		}
+	}
+
+	d, written, err := m.fetch(cmd, svc, args[0], partial, opts)
+	var apiErr *repoflow.Error
+	rangeErr := errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable
+	if opts.Offset > 0 && (rangeErr || (errors.Is(err, repoflow.ErrChecksumMismatch) && written == 0)) {
+		// The partial file is larger than the remote one, or has its size
+		// but not its content
+		m.Logger.Info("Partial file does not match the remote file, downloading the whole file", "file", m.file)
+		opts.Offset, opts.Hash = 0, nil
+		d, written, err = m.fetch(cmd, svc, args[0], partial, opts)
+	}
+	if err != nil {
+		return err
+	}
+	if written == 0 && d.Offset > 0 {
+		m.Logger.Info("File already complete", "file", m.file, "size", d.Offset)
+	}
+
+	if m.Output == "text" || m.Output == "" {
+		fmt.Printf("Successfully downloaded '%s' to '%s' (%d bytes)\n", args[0], m.file, d.Offset+written)
+		return nil
+	}
+
+	return factory.HandleOutput(m.Utils, d)
+}
+
+// fetch downloads path from opts.Offset, into partial when the server
+// resumes the download, it returns the download and the number of bytes
+// written
+func (m *DownloadManager) fetch(cmd *cobra.Command, svc repoflow.TransferService, path string, partial *os.File, opts *repoflow.DownloadOptions) (*repoflow.Download, int64, error) {
+	d, err := svc.Download(cmd.Context(), path, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer d.Close()
+
+	if d.Offset > 0 && partial != nil {
+		if _, err := partial.Seek(d.Offset, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+		written, err := io.Copy(partial, d)
+		return d, written, err
+	}
+
+	// The server sent the whole file
+	if opts.Offset > 0 {
+		m.Logger.Info("Server does not support resuming, downloading the whole file", "file", m.file)
+	}
+	written, err := m.writeFile(d)
+	return d, written, err
+}
+
+// writeFile writes r to a temporary file renamed to the destination once
+// complete, it returns the number of bytes written
+func (m *DownloadManager) writeFile(r io.Reader) (int64, error) {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(m.file); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.file), "."+filepath.Base(m.file)+".*")
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), m.file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return written, err
+}
+
+// partialFile returns the size of the partial file and the hash of its content
+func partialFile(f *os.File) (int64, hash.Hash, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, nil, err
+	}
+	return size, h, nil
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/config"
+)
+
+func TestDownloadResume(t *testing.T) {
+	content := []byte("the whole remote file")
+	sum := sha256.Sum256(content)
+	var full atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			full.Add(1)
+		}
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		local    []byte
+		sha256   bool
+		wantFull int32
+	}{
+		{"partial", content[:5], false, 0},
+		{"complete", content, false, 0},
+		{"complete and verified", content, true, 0},
+		{"larger than the remote file", append(bytes.Clone(content), "!!"...), false, 1},
+		{"same size other content", bytes.ToUpper(content), true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full.Store(0)
+			file := filepath.Join(t.TempDir(), "file")
+			if err := os.WriteFile(file, tt.local, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			u := &factory.Utils{
+				Cfg:    &config.Config{URL: srv.URL},
+				Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+				Output: "text",
+			}
+			args := []string{"/file", "--file", file, "--resume"}
+			if tt.sha256 {
+				args = append(args, "--sha256", hex.EncodeToString(sum[:]))
+			}
+
+			if _, err := execute(t, DownloadCmd(u), args...); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(file)
+			if !bytes.Equal(got, content) {
+				t.Errorf("file = %q, want %q", got, content)
+			}
+			if n := full.Load(); n != tt.wantFull {
+				t.Errorf("full downloads = %d, want %d", n, tt.wantFull)
+			}
+		})
+	}
+}
+
+func TestDownloadKeepsFileOnFailure(t *testing.T) {
+	content := []byte("the whole remote file")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"not found", []string{"/missing"}},
+		{"checksum mismatch", []string{"/file", "--sha256", strings.Repeat("0", 64)}},
+		{"resume not found", []string{"/missing", "--resume"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "file")
+			if err := os.WriteFile(file, []byte("existing"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			u := &factory.Utils{
+				Cfg:    &config.Config{URL: srv.URL},
+				Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+				Output: "text",
+			}
+
+			if _, err := execute(t, DownloadCmd(u), append(tt.args, "--file", file)...); err == nil {
+				t.Fatal("download succeeded")
+			}
+			if got, _ := os.ReadFile(file); string(got) != "existing" {
+				t.Errorf("file = %q, want it untouched", got)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+				t.Errorf("files left in %s: %v", dir, entries)
+			}
+		})
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(file, []byte("existing"), 0o600)
+	u := &factory.Utils{
+		Cfg:    &config.Config{URL: srv.URL},
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Output: "text",
+	}
+	if _, err := execute(t, DownloadCmd(u), "/file", "--file", file); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(file)
+	info, _ := os.Stat(file)
+	if !bytes.Equal(got, content) || info.Mode().Perm() != 0o600 {
+		t.Errorf("file = %q (%s), want the remote content with the mode kept", got, info.Mode())
+	}
+}
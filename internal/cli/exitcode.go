@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// Process exit codes returned by the repoflow binary
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitAPIError     = 2
+	ExitNotFound     = 3
+	ExitUnauthorized = 4
+	ExitForbidden    = 5
+	ExitConflict     = 6
+	ExitRateLimited  = 7
+	ExitCanceled     = 130
+	ExitTerminated   = 143
+)
+
+// Causes of the cancellation of a command, set by the signal handler
+var (
+	ErrInterrupted = errors.New("interrupted")
+	ErrTerminated  = errors.New("terminated")
+)
+
+// ExitCode maps a command error to the process exit code
+func ExitCode(err error) int {
+	var apiErr *repoflow.Error
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrTerminated):
+		return ExitTerminated
+	case errors.Is(err, ErrInterrupted), errors.Is(err, context.Canceled):
+		return ExitCanceled
+	case errors.Is(err, repoflow.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, repoflow.ErrUnauthorized):
+		return ExitUnauthorized
+	case errors.Is(err, repoflow.ErrForbidden):
+		return ExitForbidden
+	case errors.Is(err, repoflow.ErrConflict):
+		return ExitConflict
+	case errors.Is(err, repoflow.ErrRateLimited):
+		return ExitRateLimited
+	case errors.As(err, &apiErr):
+		return ExitAPIError
+	}
+	return ExitError
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"canceled", fmt.Errorf("request failed: %w", context.Canceled), ExitCanceled},
+		{"interrupted", fmt.Errorf("%w: %w", ErrInterrupted, context.Canceled), ExitCanceled},
+		{"terminated", fmt.Errorf("%w: %w", ErrTerminated, context.Canceled), ExitTerminated},
+		{"not found", &repoflow.Error{StatusCode: 404}, ExitNotFound},
+		{"unauthorized", &repoflow.Error{StatusCode: 401}, ExitUnauthorized},
+		{"forbidden", &repoflow.Error{StatusCode: 403}, ExitForbidden},
+		{"conflict", &repoflow.Error{StatusCode: 409}, ExitConflict},
+		{"rate limited", &repoflow.Error{StatusCode: 429}, ExitRateLimited},
+		{"other api error", &repoflow.Error{StatusCode: 500}, ExitAPIError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -22,6 +24,21 @@ type RepositoryManager struct {
 	isRemoteCacheEnabled              bool
 	fileCacheTimeTillRevalidation     *int
 	metadataCacheTimeTillRevalidation *int
+	limit                             int
+	offset                            int
+	all                               bool
+	bulk                              bulkFlags
+	rename                            *string
+	remoteUpdate                      repoflow.RepositoryRemoteUpdateOptions
+	virtualUpdate                     repoflow.RepositoryVirtualUpdateOptions
+	clearUploadTarget                 bool
+	clone                             cloneFlags
+}
+
+// updateFlags lists the update flags specific to a store type
+var updateFlags = map[string][]string{
+	"remote":  {"remote-url", "remote-username", "remote-password", "cache", "file-cache-ttr", "metadata-cache-ttr"},
+	"virtual": {"child-repository", "local-repository"},
 }
 
 // RepositoryCmd initializes the parent command and its subcommands
@@ -49,30 +66,42 @@ func RepositoryCmd(u *factory.Utils) *cobra.Command {
 
 	// Get sub-command
 	var getCmd = &cobra.Command{
-		Use:          "get [name]",
-		Short:        "Get repository metadata (ID or name)",
-		Args:         cobra.ExactArgs(1),
+		Use:          "get [name...]",
+		Short:        "Get repository metadata (IDs or names)",
 		RunE:         m.repositoryGet,
 		SilenceUsage: true,
 	}
+	m.bulk.register(getCmd)
 
 	// Delete sub-command
 	var deleteCmd = &cobra.Command{
-		Use:          "delete [name]",
-		Short:        "Delete a repository (ID or name)",
-		Args:         cobra.ExactArgs(1),
+		Use:          "delete [name...]",
+		Short:        "Delete repositories (IDs or names)",
 		RunE:         m.repositoryDelete,
 		SilenceUsage: true,
 	}
+	m.bulk.register(deleteCmd)
 
 	// Delete sub-command
 	var deleteContentCmd = &cobra.Command{
-		Use:          "prune [name]",
-		Short:        "Delete a repository content (ID or name)",
-		Args:         cobra.ExactArgs(1),
+		Use:          "prune [name...]",
+		Short:        "Delete repositories content (IDs or names)",
 		RunE:         m.repositoryDeleteContent,
 		SilenceUsage: true,
 	}
+	m.bulk.register(deleteContentCmd)
+
+	// Packages sub-command
+	var packagesCmd = &cobra.Command{
+		Use:          "packages [name]",
+		Short:        "List packages stored in a repository (ID or name)",
+		Args:         cobra.ExactArgs(1),
+		RunE:         m.repositoryPackages,
+		SilenceUsage: true,
+	}
+	packagesCmd.Flags().IntVar(&m.limit, "limit", repoflow.DefaultPageSize, "Maximum number of packages per page")
+	packagesCmd.Flags().IntVar(&m.offset, "offset", 0, "Number of packages to skip")
+	packagesCmd.Flags().BoolVar(&m.all, "all", false, "Fetch all pages, printed as they arrive (one JSON object per line with -o json)")
 
 	// Create repository
 	var createCmd = &cobra.Command{
@@ -157,20 +186,96 @@ func RepositoryCmd(u *factory.Utils) *cobra.Command {
 
 	createVirtualCmd.Flags().StringSliceVarP(
 		&m.childRepositoryIds, "child-repository", "r", []string{},
-		"IDs of repositories included in the virtual repository.",
+		"IDs or names of repositories included in the virtual repository.",
 	)
 	createVirtualCmd.Flags().StringVar(
 		&m.uploadLocalRepositoryId, "local-repository", "",
-		"ID of a local repository where uploads will be stored (must also be in childRepositoryIds).",
+		"ID or name of a local repository where uploads will be stored (must also be in childRepositoryIds).",
 	)
 
 	createVirtualCmd.MarkFlagRequired("child-repository")
 
 	createCmd.AddCommand(createLocalCmd, createRemoteCmd, createVirtualCmd)
 
+	// Update sub-command, only the flags set are sent
+	var (
+		rename                   string
+		updateUrl                string
+		updateUsername           string
+		updatePassword           string
+		updateCache              bool
+		updateFileCacheTTR       int
+		updateMetadataCacheTTR   int
+		updateChildRepositories  []string
+		updateUploadRepositoryId string
+	)
+	var updateCmd = &cobra.Command{
+		Use:          "update [name]",
+		Short:        "Update a repository (ID or name), only the flags set are changed",
+		Args:         cobra.ExactArgs(1),
+		RunE:         m.repositoryUpdate,
+		SilenceUsage: true,
+	}
+	updateCmd.Flags().StringVar(&rename, "name", "", "New name of the repository")
+	updateCmd.Flags().StringVar(&updateUrl, "remote-url", "", "URL of the remote repository (remote)")
+	updateCmd.Flags().StringVar(&updateUsername, "remote-username", "", "Username of the remote repository (remote)")
+	updateCmd.Flags().StringVar(&updatePassword, "remote-password", "", "Password for the remote repository (remote)")
+	updateCmd.Flags().BoolVar(&updateCache, "cache", false, "Whether caching is enabled (remote)")
+	updateCmd.Flags().IntVar(
+		&updateFileCacheTTR, "file-cache-ttr", -1,
+		"Milliseconds before cached files require revalidation, -1 for indefinite caching (remote)",
+	)
+	updateCmd.Flags().IntVar(
+		&updateMetadataCacheTTR, "metadata-cache-ttr", -1,
+		"Milliseconds before cached metadata requires revalidation, -1 for indefinite caching (remote)",
+	)
+	updateCmd.Flags().StringSliceVar(
+		&updateChildRepositories, "child-repository", []string{},
+		"IDs or names of repositories included in the virtual repository, replacing the current ones (virtual)",
+	)
+	updateCmd.Flags().StringVar(
+		&updateUploadRepositoryId, "local-repository", "",
+		"ID or name of the local child repository storing uploads, empty to remove it (virtual)",
+	)
+	updateCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		m.rename = nil
+		m.remoteUpdate = repoflow.RepositoryRemoteUpdateOptions{}
+		m.virtualUpdate = repoflow.RepositoryVirtualUpdateOptions{}
+
+		if cmd.Flags().Changed("name") {
+			m.rename = &rename
+		}
+		if cmd.Flags().Changed("remote-url") {
+			m.remoteUpdate.RemoteRepositoryUrl = &updateUrl
+		}
+		if cmd.Flags().Changed("remote-username") {
+			m.remoteUpdate.RemoteRepositoryUsername = &updateUsername
+		}
+		if cmd.Flags().Changed("remote-password") {
+			m.remoteUpdate.RemoteRepositoryPassword = &updatePassword
+		}
+		if cmd.Flags().Changed("cache") {
+			m.remoteUpdate.IsRemoteCacheEnabled = &updateCache
+		}
+		if cmd.Flags().Changed("file-cache-ttr") {
+			m.remoteUpdate.FileCacheTimeTillRevalidation = &updateFileCacheTTR
+		}
+		if cmd.Flags().Changed("metadata-cache-ttr") {
+			m.remoteUpdate.MetadataCacheTimeTillRevalidation = &updateMetadataCacheTTR
+		}
+		if cmd.Flags().Changed("child-repository") {
+			m.virtualUpdate.ChildRepositoryIds = &updateChildRepositories
+		}
+		if cmd.Flags().Changed("local-repository") {
+			m.virtualUpdate.UploadLocalRepositoryId = &updateUploadRepositoryId
+		}
+		return nil
+	}
+
 	// Register sub-commands
 	repositoryCmd.AddCommand(
-		listCmd, createCmd, getCmd, deleteCmd, deleteContentCmd,
+		listCmd, createCmd, getCmd, deleteCmd, deleteContentCmd, packagesCmd, updateCmd, virtualCmd(m),
+		cloneCmd(m),
 	)
 
 	return repositoryCmd
@@ -178,7 +283,17 @@ func RepositoryCmd(u *factory.Utils) *cobra.Command {
 
 // --- Runners Implementation ---
 func (m *RepositoryManager) repositoryList(cmd *cobra.Command, args []string) error {
-	data, err := m.GetAPIClient().ListRepositories(m.workspace)
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	wsID, err := m.ResolveWorkspace(cmd.Context(), m.workspace)
+	if err != nil {
+		return err
+	}
+
+	data, err := svc.ListRepositories(cmd.Context(), wsID)
 	if err != nil {
 		return err
 	}
@@ -186,21 +301,106 @@ func (m *RepositoryManager) repositoryList(cmd *cobra.Command, args []string) er
 }
 
 func (m *RepositoryManager) repositoryGet(cmd *cobra.Command, args []string) error {
-	data, err := m.GetAPIClient().GetRepository(m.workspace, args[0])
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	get := func(ctx context.Context, ref string) (*repoflow.Repository, error) {
+		wsID, repoID, err := m.ResolveRepository(ctx, m.workspace, ref)
+		if err != nil {
+			return nil, err
+		}
+		return svc.GetRepository(ctx, wsID, repoID)
+	}
+
+	items, err := m.bulk.items(args)
+	if err != nil {
+		return err
+	}
+	if !m.bulk.single(items) {
+		return runBulk(cmd.Context(), m.Utils, &m.bulk, items, get)
+	}
+
+	data, err := get(cmd.Context(), items[0])
 	if err != nil {
 		return err
 	}
 	return factory.HandleOutput(m.Utils, data)
 }
 
+func (m *RepositoryManager) repositoryPackages(cmd *cobra.Command, args []string) error {
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	wsID, repoID, err := m.ResolveRepository(cmd.Context(), m.workspace, args[0])
+	if err != nil {
+		return err
+	}
+
+	opts := &repoflow.ListOptions{Offset: m.offset, Limit: m.limit}
+
+	if !m.all {
+		data, err := svc.ListRepositoryPackages(cmd.Context(), wsID, repoID, opts)
+		if err != nil {
+			return err
+		}
+		if m.Output == "text" || m.Output == "" {
+			if err := factory.HandleOutput(m.Utils, data.Packages); err != nil {
+				return err
+			}
+			fmt.Printf("\nShowing %d package(s) from offset %d of %d\n", len(data.Packages), data.Offset, data.Total)
+			return nil
+		}
+		return factory.HandleOutput(m.Utils, data)
+	}
+
+	// Packages are printed as the pages arrive
+	out := m.NewItemWriter(os.Stdout)
+	for pkg, err := range svc.AllRepositoryPackages(cmd.Context(), wsID, repoID, opts) {
+		if err == nil {
+			err = out.Write(pkg)
+		}
+		if err != nil {
+			// The rows already received are still flushed
+			out.Close()
+			return err
+		}
+	}
+	return out.Close()
+}
+
 func (m *RepositoryManager) repositoryDelete(cmd *cobra.Command, args []string) error {
-	data, err := m.GetAPIClient().DeleteRepository(m.workspace, args[0])
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	del := func(ctx context.Context, ref string) (*repoflow.RepostotryDelete, error) {
+		wsID, repoID, err := m.ResolveRepository(ctx, m.workspace, ref)
+		if err != nil {
+			return nil, err
+		}
+		return svc.DeleteRepository(ctx, wsID, repoID)
+	}
+
+	items, err := m.bulk.items(args)
+	if err != nil {
+		return err
+	}
+	if !m.bulk.single(items) {
+		return runBulk(cmd.Context(), m.Utils, &m.bulk, items, del)
+	}
+
+	data, err := del(cmd.Context(), items[0])
 	if err != nil {
 		return err
 	}
 
 	if m.Output == "text" || m.Output == "" {
-		fmt.Printf("Successfully deleted repository '%s' workspace '%s'\n", args[0], m.workspace)
+		fmt.Printf("Successfully deleted repository '%s' workspace '%s'\n", items[0], m.workspace)
 		return nil
 	}
 
@@ -210,6 +410,11 @@ func (m *RepositoryManager) repositoryDelete(cmd *cobra.Command, args []string)
 func (m *RepositoryManager) repositoryCreate(cmd *cobra.Command, args []string, store string) error {
 	name := args[0]
 
+	wsID, err := m.ResolveWorkspace(cmd.Context(), m.workspace)
+	if err != nil {
+		return err
+	}
+
 	var opts any
 
 	switch store {
@@ -232,18 +437,39 @@ func (m *RepositoryManager) repositoryCreate(cmd *cobra.Command, args []string,
 		}
 
 	case "virtual":
+		childIds := make([]string, 0, len(m.childRepositoryIds))
+		for _, child := range m.childRepositoryIds {
+			_, childID, err := m.ResolveRepository(cmd.Context(), wsID, child)
+			if err != nil {
+				return err
+			}
+			childIds = append(childIds, childID)
+		}
+
+		uploadID := ""
+		if m.uploadLocalRepositoryId != "" {
+			if _, uploadID, err = m.ResolveRepository(cmd.Context(), wsID, m.uploadLocalRepositoryId); err != nil {
+				return err
+			}
+		}
+
 		opts = repoflow.RepositoryVirtualOptions{
 			Name:                    name,
 			PackageType:             m.packageType,
-			ChildRepositoryIds:      m.childRepositoryIds,
-			UploadLocalRepositoryId: m.uploadLocalRepositoryId,
+			ChildRepositoryIds:      childIds,
+			UploadLocalRepositoryId: uploadID,
 		}
 
 	default:
 		return fmt.Errorf("Unsuported store store type: %s", store)
 	}
 
-	data, err := m.GetAPIClient().CreateRepository(m.workspace, store, opts)
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	data, err := svc.CreateRepository(cmd.Context(), wsID, store, opts)
 	if err != nil {
 		return err
 	}
@@ -256,16 +482,119 @@ func (m *RepositoryManager) repositoryCreate(cmd *cobra.Command, args []string,
 	return factory.HandleOutput(m.Utils, data)
 }
 
+func (m *RepositoryManager) repositoryUpdate(cmd *cobra.Command, args []string) error {
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	wsID, repoID, err := m.ResolveRepository(cmd.Context(), m.workspace, args[0])
+	if err != nil {
+		return err
+	}
+
+	repo, err := svc.GetRepository(cmd.Context(), wsID, repoID)
+	if err != nil {
+		return err
+	}
+	store := repo.RepositoryType
+
+	changed := m.rename != nil
+	for flagStore, flags := range updateFlags {
+		for _, flag := range flags {
+			if !cmd.Flags().Changed(flag) {
+				continue
+			}
+			if flagStore != store {
+				return fmt.Errorf("--%s only applies to %s repositories, '%s' is a %s repository", flag, flagStore, args[0], store)
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return fmt.Errorf("nothing to update, set at least one flag")
+	}
+
+	var opts any
+
+	switch store {
+	case "local":
+		opts = repoflow.RepositoryUpdateOptions{Name: m.rename}
+
+	case "remote":
+		update := m.remoteUpdate
+		update.Name = m.rename
+		opts = update
+
+	case "virtual":
+		update := m.virtualUpdate
+		update.Name = m.rename
+		if update.ChildRepositoryIds != nil {
+			childIds := make([]string, 0, len(*update.ChildRepositoryIds))
+			for _, child := range *update.ChildRepositoryIds {
+				_, childID, err := m.ResolveRepository(cmd.Context(), wsID, child)
+				if err != nil {
+					return err
+				}
+				childIds = append(childIds, childID)
+			}
+			update.ChildRepositoryIds = &childIds
+		}
+		if update.UploadLocalRepositoryId != nil && *update.UploadLocalRepositoryId != "" {
+			_, uploadID, err := m.ResolveRepository(cmd.Context(), wsID, *update.UploadLocalRepositoryId)
+			if err != nil {
+				return err
+			}
+			update.UploadLocalRepositoryId = &uploadID
+		}
+		opts = update
+
+	default:
+		return fmt.Errorf("Unsuported store store type: %s", store)
+	}
+
+	data, err := svc.UpdateRepository(cmd.Context(), wsID, store, repoID, opts)
+	if err != nil {
+		return err
+	}
+
+	if m.Output == "text" || m.Output == "" {
+		fmt.Printf("Successfully updated repository '%s' on workspace '%s'.\n", args[0], m.workspace)
+		return nil
+	}
+
+	return factory.HandleOutput(m.Utils, data)
+}
+
 func (m *RepositoryManager) repositoryDeleteContent(cmd *cobra.Command, args []string) error {
-	name := args[0]
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	prune := func(ctx context.Context, ref string) (*repoflow.RepostotryDelete, error) {
+		wsID, repoID, err := m.ResolveRepository(ctx, m.workspace, ref)
+		if err != nil {
+			return nil, err
+		}
+		return svc.DeleteRepositoryContent(ctx, wsID, repoID)
+	}
+
+	items, err := m.bulk.items(args)
+	if err != nil {
+		return err
+	}
+	if !m.bulk.single(items) {
+		return runBulk(cmd.Context(), m.Utils, &m.bulk, items, prune)
+	}
 
-	data, err := m.GetAPIClient().DeleteRepositoryContent(m.workspace, name)
+	data, err := prune(cmd.Context(), items[0])
 	if err != nil {
 		return err
 	}
 
 	if m.Output == "text" || m.Output == "" {
-		fmt.Printf("Successfully delete content on repository '%s' workspace '%s'\n", args[0], m.workspace)
+		fmt.Printf("Successfully delete content on repository '%s' workspace '%s'\n", items[0], m.workspace)
 		return nil
 	}
 
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+// newFakeUtils returns utils without configuration, every API call must go
+// through the services set by the test
+func newFakeUtils(output string) *factory.Utils {
+	return &factory.Utils{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Output: output,
+	}
+}
+
+func TestStatusUsesStatusService(t *testing.T) {
+	u := newFakeUtils("json")
+	u.Status = &repoflowtest.FakeStatusService{
+		ServerInfoFunc: func(ctx context.Context) (*repoflow.ServerInfo, error) {
+			return &repoflow.ServerInfo{URL: "http://fake", Version: "9.9.9"}, nil
+		},
+	}
+
+	out, err := execute(t, StatusCmd(u))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "9.9.9") {
+		t.Errorf("status output = %q", out)
+	}
+}
+
+func TestStatusFailure(t *testing.T) {
+	failure := &repoflow.Error{StatusCode: http.StatusInternalServerError}
+	tests := []struct {
+		name   string
+		info   *repoflow.ServerInfo
+		output string
+		want   []string
+	}{
+		{"partway", &repoflow.ServerInfo{URL: "http://fake", Version: "9.9.9"}, "text", []string{"http://fake", "KO", "500"}},
+		{"without info", nil, "text", []string{"unknown", "KO", "500"}},
+		{"without info as json", nil, "json", []string{`"url"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newFakeUtils(tt.output)
+			u.Status = &repoflowtest.FakeStatusService{
+				ServerInfoFunc: func(ctx context.Context) (*repoflow.ServerInfo, error) {
+					return tt.info, failure
+				},
+			}
+
+			out, err := execute(t, StatusCmd(u))
+			if !errors.Is(err, failure) {
+				t.Errorf("error = %v, want the service error", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("status output misses %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+
+	// The default fake returns no information
+	u := newFakeUtils("text")
+	u.Status = &repoflowtest.FakeStatusService{}
+	if _, err := execute(t, StatusCmd(u)); !errors.Is(err, repoflowtest.ErrNotImplemented) {
+		t.Errorf("error = %v, want ErrNotImplemented", err)
+	}
+}
+
+func TestTransfersUseTransferService(t *testing.T) {
+	u := newFakeUtils("text")
+	var uploaded string
+	u.Transfers = &repoflowtest.FakeTransferService{
+		DownloadFunc: func(ctx context.Context, path string, opts *repoflow.DownloadOptions) (*repoflow.Download, error) {
+			return repoflow.NewDownload(io.NopCloser(strings.NewReader("content of " + path))), nil
+		},
+		UploadFunc: func(ctx context.Context, path string, content io.Reader, opts *repoflow.UploadOptions) (*repoflow.UploadResult, error) {
+			data, err := io.ReadAll(content)
+			uploaded = string(data)
+			return &repoflow.UploadResult{Size: int64(len(data))}, err
+		},
+	}
+
+	out, err := execute(t, DownloadCmd(u), "files/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "content of files/a.txt" {
+		t.Errorf("download output = %q", out)
+	}
+
+	file := t.TempDir() + "/b.txt"
+	if _, err := execute(t, DownloadCmd(u), "files/b.txt", "--file", file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execute(t, UploadCmd(u), file, "files/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if uploaded != "content of files/b.txt" {
+		t.Errorf("uploaded = %q", uploaded)
+	}
+}
+
+func TestAPIUsesRequestService(t *testing.T) {
+	u := newFakeUtils("text")
+	var paths []string
+	u.Requests = &repoflowtest.FakeRequestService{
+		DoRequestFunc: func(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+			paths = append(paths, method+" "+path)
+			if strings.Contains(path, "offset=2") {
+				return json.Unmarshal([]byte(`{"total":3,"packages":[{"id":"c"}]}`), result)
+			}
+			return json.Unmarshal([]byte(`{"total":3,"packages":[{"id":"a"},{"id":"b"}]}`), result)
+		},
+	}
+
+	out, err := execute(t, APICmd(u), "GET", "/1/packages?limit=2", "--paginate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []map[string]string
+	if err := json.Unmarshal([]byte(out), &items); err != nil {
+		t.Fatalf("api output %q: %v", out, err)
+	}
+	if len(items) != 3 || len(paths) != 2 {
+		t.Errorf("items = %v, requests = %v", items, paths)
+	}
+}
+
+func TestRepositoryListUsesServices(t *testing.T) {
+	u := newFakeUtils("json")
+	notFound := &repoflow.Error{StatusCode: http.StatusNotFound}
+	u.Workspaces = &repoflowtest.FakeWorkspaceService{
+		// References are names, the lookups by ID fail
+		GetWorkspaceFunc: func(ctx context.Context, id string) (*repoflow.Workspace, error) {
+			return nil, notFound
+		},
+		ListWorkspacesFunc: func(ctx context.Context) (*[]repoflow.Workspaces, error) {
+			return &[]repoflow.Workspaces{{Id: "ws-1", Name: "team"}}, nil
+		},
+	}
+	u.Repositories = &repoflowtest.FakeRepositoryService{
+		GetRepositoryFunc: func(ctx context.Context, workspace string, id string) (*repoflow.Repository, error) {
+			return nil, notFound
+		},
+		ListRepositoriesFunc: func(ctx context.Context, workspace string) (*[]repoflow.Repositories, error) {
+			if workspace != "ws-1" {
+				return nil, repoflow.ErrNotFound
+			}
+			return &[]repoflow.Repositories{{Id: "repo-1", Name: "npm"}}, nil
+		},
+	}
+
+	out, err := execute(t, RepositoryCmd(u), "list", "-w", "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "repo-1") {
+		t.Errorf("list output = %q", out)
+	}
+
+	// Unset methods report ErrNotImplemented instead of reaching a server
+	_, err = execute(t, RepositoryCmd(u), "delete", "npm", "-w", "team")
+	if !errors.Is(err, repoflowtest.ErrNotImplemented) {
+		t.Errorf("delete error = %v, want ErrNotImplemented", err)
+	}
+}
+
+func TestRepositoryPackagesAllFlushesOnError(t *testing.T) {
+	u := newFakeUtils("text")
+	u.Workspaces = &repoflowtest.FakeWorkspaceService{
+		GetWorkspaceFunc: func(ctx context.Context, id string) (*repoflow.Workspace, error) {
+			return &repoflow.Workspace{Id: id, Name: "team"}, nil
+		},
+	}
+	u.Repositories = &repoflowtest.FakeRepositoryService{
+		GetRepositoryFunc: func(ctx context.Context, workspace string, id string) (*repoflow.Repository, error) {
+			return &repoflow.Repository{Id: id, Name: "npm"}, nil
+		},
+		AllRepositoryPackagesFunc: func(ctx context.Context, workspace string, id string, opts *repoflow.ListOptions) iter.Seq2[*repoflow.PackageRepository, error] {
+			return func(yield func(*repoflow.PackageRepository, error) bool) {
+				_ = yield(&repoflow.PackageRepository{Id: "pkg-1", Name: "left-pad"}, nil) &&
+					yield(nil, &repoflow.Error{StatusCode: http.StatusInternalServerError})
+			}
+		},
+	}
+
+	out, err := execute(t, RepositoryCmd(u), "packages", "repo-1", "-w", "ws-1", "--all")
+	if ExitCode(err) != ExitAPIError {
+		t.Errorf("error = %v, want the API error", err)
+	}
+	if !strings.Contains(out, "left-pad") {
+		t.Errorf("rows received before the error not flushed: %q", out)
+	}
+}
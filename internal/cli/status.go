@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// StatusManager handles the state and configuration for the status command
+type StatusManager struct {
+	*factory.Utils
+}
+
+// StatusCmd initializes the status command
+func StatusCmd(u *factory.Utils) *cobra.Command {
+	m := &StatusManager{Utils: u}
+
+	var statusCmd = &cobra.Command{
+		Use:          "status",
+		Short:        "Check the server is reachable and the token valid, exit non-zero otherwise",
+		Args:         cobra.NoArgs,
+		RunE:         m.status,
+		SilenceUsage: true,
+	}
+
+	return statusCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *StatusManager) status(cmd *cobra.Command, args []string) error {
+	svc, err := m.GetStatusService()
+	if err != nil {
+		return err
+	}
+
+	info, err := svc.ServerInfo(cmd.Context())
+	if info == nil {
+		info = &repoflow.ServerInfo{}
+		if m.Cfg != nil {
+			info.URL = m.Cfg.URL
+		}
+	}
+
+	if m.Output == "text" || m.Output == "" {
+		printStatus(info, err)
+	} else if outErr := factory.HandleOutput(m.Utils, info); outErr != nil {
+		return outErr
+	}
+
+	return err
+}
+
+// printStatus writes the server information as aligned key values
+func printStatus(info *repoflow.ServerInfo, err error) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "URL:\t%s\n", valueOrUnknown(info.URL))
+	if err != nil {
+		fmt.Fprintf(w, "Status:\tKO\n")
+		fmt.Fprintf(w, "Error:\t%s\n", err)
+		return
+	}
+	fmt.Fprintf(w, "Status:\tOK\n")
+	fmt.Fprintf(w, "Latency:\t%s\n", info.Latency.Round(time.Millisecond))
+	fmt.Fprintf(w, "Version:\t%s\n", valueOrUnknown(info.Version))
+	if len(info.Capabilities) > 0 {
+		fmt.Fprintf(w, "Capabilities:\t%s\n", strings.Join(info.Capabilities, ", "))
+	}
+	if info.Identity != nil {
+		fmt.Fprintf(w, "Identity:\t%s (%s)\n", info.Identity.Username, info.Identity.Id)
+	} else {
+		fmt.Fprintf(w, "Identity:\tunknown\n")
+	}
+	if info.TLS != nil {
+		fmt.Fprintf(w, "TLS:\t%s, %s\n", info.TLS.Version, info.TLS.CipherSuite)
+		fmt.Fprintf(w, "Certificate:\t%s\n", info.TLS.Subject)
+		fmt.Fprintf(w, "Issuer:\t%s\n", info.TLS.Issuer)
+		fmt.Fprintf(w, "Expires:\t%s\n", info.TLS.NotAfter.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(w, "TLS:\tnone\n")
+	}
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
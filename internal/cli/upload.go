@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// UploadManager handles the state and configuration for the upload command
+type UploadManager struct {
+	*factory.Utils
+	method      string
+	contentType string
+	multipart   bool
+	field       string
+	fileName    string
+	fields      []string
+	progress    bool
+}
+
+// UploadCmd initializes the upload command
+func UploadCmd(u *factory.Utils) *cobra.Command {
+	m := &UploadManager{Utils: u}
+
+	var uploadCmd = &cobra.Command{
+		Use:          "upload [file] [path]",
+		Short:        "Upload a file (path relative to the API url or absolute url), - reads stdin",
+		Args:         cobra.ExactArgs(2),
+		RunE:         m.upload,
+		SilenceUsage: true,
+	}
+
+	uploadCmd.Flags().StringVarP(&m.method, "method", "X", "PUT", "HTTP method")
+	uploadCmd.Flags().StringVar(&m.contentType, "content-type", "", "Content type of the file, also sent as the file part type with --multipart")
+	uploadCmd.Flags().BoolVar(&m.multipart, "multipart", false, "Send the file as multipart/form-data")
+	uploadCmd.Flags().StringVar(&m.field, "field", "file", "Form field of the file (multipart)")
+	uploadCmd.Flags().StringVar(&m.fileName, "filename", "", "File name sent in the form (multipart), defaults to the file base name")
+	uploadCmd.Flags().StringArrayVarP(&m.fields, "form", "F", nil, "Extra form field key=value (multipart), can be repeated")
+	uploadCmd.Flags().BoolVar(&m.progress, "progress", isTerminal(os.Stderr), "Show the upload progress on stderr, by default when it is a terminal")
+
+	return uploadCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *UploadManager) upload(cmd *cobra.Command, args []string) error {
+	svc, err := m.GetTransferService()
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]string{}
+	for _, field := range m.fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return fmt.Errorf("invalid form field %q, expected key=value", field)
+		}
+		fields[key] = value
+	}
+	if len(fields) > 0 && !m.multipart {
+		return fmt.Errorf("--form requires --multipart")
+	}
+
+	var content io.Reader = os.Stdin
+	fileName := m.fileName
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		content = f
+		if fileName == "" {
+			fileName = filepath.Base(args[0])
+		}
+	}
+
+	opts := &repoflow.UploadOptions{
+		Method:        strings.ToUpper(m.method),
+		ContentType:   m.contentType,
+		ContentLength: -1,
+		Multipart:     m.multipart,
+		FieldName:     m.field,
+		FileName:      fileName,
+		Fields:        fields,
+	}
+	if m.progress {
+		opts.Progress = progressPrinter(os.Stderr)
+	}
+
+	result, err := svc.Upload(cmd.Context(), args[1], content, opts)
+	if m.progress {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if m.Output == "text" || m.Output == "" {
+		fmt.Printf("Successfully uploaded '%s' to '%s' (%d bytes, sha256 %s)\n", args[0], args[1], result.Size, result.SHA256)
+		return nil
+	}
+
+	return factory.HandleOutput(m.Utils, result)
+}
+
+// progressPrinter returns a progress callback refreshing a single line of w
+// at most ten times per second
+func progressPrinter(w io.Writer) repoflow.ProgressFunc {
+	var last time.Time
+	return func(sent, total int64) {
+		if time.Since(last) < 100*time.Millisecond && sent != total {
+			return
+		}
+		last = time.Now()
+		if total > 0 {
+			fmt.Fprintf(w, "\r%d / %d bytes (%d%%)", sent, total, sent*100/total)
+			return
+		}
+		fmt.Fprintf(w, "\r%d bytes", sent)
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fe80/go-repoflow/internal/factory"
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// virtualCmd initializes the commands changing the members of a virtual
+// repository, they share the repository manager and its workspace flag
+func virtualCmd(m *RepositoryManager) *cobra.Command {
+	var virtualCmd = &cobra.Command{
+		Use:   "virtual",
+		Short: "Manage the members of a virtual repository",
+	}
+
+	// Add child sub-command
+	var addChildCmd = &cobra.Command{
+		Use:          "add-child [virtual] [child...]",
+		Short:        "Append children to a virtual repository (IDs or names)",
+		Args:         cobra.MinimumNArgs(2),
+		RunE:         m.virtualAddChild,
+		SilenceUsage: true,
+	}
+
+	// Remove child sub-command
+	var removeChildCmd = &cobra.Command{
+		Use:          "remove-child [virtual] [child...]",
+		Short:        "Remove children from a virtual repository (IDs or names)",
+		Args:         cobra.MinimumNArgs(2),
+		RunE:         m.virtualRemoveChild,
+		SilenceUsage: true,
+	}
+
+	// Reorder sub-command
+	var reorderCmd = &cobra.Command{
+		Use:          "reorder [virtual] [child...]",
+		Short:        "Set the resolution order of every child of a virtual repository (IDs or names)",
+		Args:         cobra.MinimumNArgs(2),
+		RunE:         m.virtualReorder,
+		SilenceUsage: true,
+	}
+
+	// Set upload target sub-command
+	var setUploadTargetCmd = &cobra.Command{
+		Use:          "set-upload-target [virtual] [local]",
+		Short:        "Set the local child storing the uploads of a virtual repository (IDs or names)",
+		Args:         cobra.RangeArgs(1, 2),
+		RunE:         m.virtualSetUploadTarget,
+		SilenceUsage: true,
+	}
+	setUploadTargetCmd.Flags().BoolVar(&m.clearUploadTarget, "clear", false, "Remove the upload target")
+
+	virtualCmd.AddCommand(addChildCmd, removeChildCmd, reorderCmd, setUploadTargetCmd)
+
+	return virtualCmd
+}
+
+// --- Runners Implementation ---
+
+func (m *RepositoryManager) virtualAddChild(cmd *cobra.Command, args []string) error {
+	return m.virtualChange(cmd, args[0], "updated children of", func(ctx context.Context, v *repoflow.VirtualManager, wsID, repoID string) (*repoflow.Repository, error) {
+		children, err := m.resolveRepositories(ctx, wsID, args[1:])
+		if err != nil {
+			return nil, err
+		}
+		return v.AddChildren(ctx, wsID, repoID, children...)
+	})
+}
+
+func (m *RepositoryManager) virtualRemoveChild(cmd *cobra.Command, args []string) error {
+	return m.virtualChange(cmd, args[0], "updated children of", func(ctx context.Context, v *repoflow.VirtualManager, wsID, repoID string) (*repoflow.Repository, error) {
+		children, err := m.resolveRepositories(ctx, wsID, args[1:])
+		if err != nil {
+			return nil, err
+		}
+		return v.RemoveChildren(ctx, wsID, repoID, children...)
+	})
+}
+
+func (m *RepositoryManager) virtualReorder(cmd *cobra.Command, args []string) error {
+	return m.virtualChange(cmd, args[0], "reordered children of", func(ctx context.Context, v *repoflow.VirtualManager, wsID, repoID string) (*repoflow.Repository, error) {
+		children, err := m.resolveRepositories(ctx, wsID, args[1:])
+		if err != nil {
+			return nil, err
+		}
+		return v.Reorder(ctx, wsID, repoID, children)
+	})
+}
+
+func (m *RepositoryManager) virtualSetUploadTarget(cmd *cobra.Command, args []string) error {
+	if len(args) == 2 && m.clearUploadTarget {
+		return fmt.Errorf("--clear cannot be used with a local repository")
+	}
+	if len(args) == 1 && !m.clearUploadTarget {
+		return fmt.Errorf("requires a local repository, or --clear to remove the upload target")
+	}
+
+	return m.virtualChange(cmd, args[0], "set upload target of", func(ctx context.Context, v *repoflow.VirtualManager, wsID, repoID string) (*repoflow.Repository, error) {
+		localID := ""
+		if len(args) == 2 {
+			local, err := m.resolveRepositories(ctx, wsID, args[1:])
+			if err != nil {
+				return nil, err
+			}
+			localID = local[0]
+		}
+		return v.SetUploadTarget(ctx, wsID, repoID, localID)
+	})
+}
+
+// virtualChange resolves the virtual repository ref, applies change and
+// prints the updated repository
+func (m *RepositoryManager) virtualChange(
+	cmd *cobra.Command, ref string, action string,
+	change func(ctx context.Context, v *repoflow.VirtualManager, wsID, repoID string) (*repoflow.Repository, error),
+) error {
+	svc, err := m.GetRepositoryService()
+	if err != nil {
+		return err
+	}
+
+	wsID, repoID, err := m.ResolveRepository(cmd.Context(), m.workspace, ref)
+	if err != nil {
+		return err
+	}
+
+	data, err := change(cmd.Context(), repoflow.NewVirtualManager(svc), wsID, repoID)
+	if err != nil {
+		return err
+	}
+
+	if m.Output == "text" || m.Output == "" {
+		fmt.Printf("Successfully %s virtual repository '%s' on workspace '%s'.\n", action, ref, m.workspace)
+		return nil
+	}
+
+	return factory.HandleOutput(m.Utils, data)
+}
+
+// resolveRepositories returns the IDs of the repositories designated by refs
+func (m *RepositoryManager) resolveRepositories(ctx context.Context, wsID string, refs []string) ([]string, error) {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		_, id, err := m.ResolveRepository(ctx, wsID, ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -16,6 +17,7 @@ type WorkspaceManager struct {
 	bandwidthLimit *int
 	storageLimit   *int
 	comments       *string
+	bulk           bulkFlags
 }
 
 // WorkspaceCmd initializes the parent command and its subcommands
@@ -38,21 +40,21 @@ func WorkspaceCmd(u *factory.Utils) *cobra.Command {
 
 	// Get sub-command
 	var getCmd = &cobra.Command{
-		Use:          "get [name]",
-		Short:        "Get workspace metadata (workspace ID or name)",
-		Args:         cobra.ExactArgs(1),
+		Use:          "get [name...]",
+		Short:        "Get workspace metadata (workspace IDs or names)",
 		RunE:         m.workspaceGet,
 		SilenceUsage: true,
 	}
+	m.bulk.register(getCmd)
 
 	// Delete sub-command
 	var deleteCmd = &cobra.Command{
-		Use:          "delete [name]",
-		Short:        "Delete a workspace (workspace ID or name)",
-		Args:         cobra.ExactArgs(1),
+		Use:          "delete [name...]",
+		Short:        "Delete workspaces (workspace IDs or names)",
 		RunE:         m.workspaceDelete,
 		SilenceUsage: true,
 	}
+	m.bulk.register(deleteCmd)
 
 	// Create sub-command with flags
 	var (
@@ -97,7 +99,12 @@ func WorkspaceCmd(u *factory.Utils) *cobra.Command {
 // --- Runners Implementation ---
 
 func (m *WorkspaceManager) workspaceList(cmd *cobra.Command, args []string) error {
-	data, err := m.GetAPIClient().ListWorkspaces()
+	svc, err := m.GetWorkspaceService()
+	if err != nil {
+		return err
+	}
+
+	data, err := svc.ListWorkspaces(cmd.Context())
 	if err != nil {
 		return err
 	}
@@ -105,7 +112,28 @@ func (m *WorkspaceManager) workspaceList(cmd *cobra.Command, args []string) erro
 }
 
 func (m *WorkspaceManager) workspaceGet(cmd *cobra.Command, args []string) error {
-	data, err := m.GetAPIClient().GetWorkspace(args[0])
+	svc, err := m.GetWorkspaceService()
+	if err != nil {
+		return err
+	}
+
+	get := func(ctx context.Context, ref string) (*repoflow.Workspace, error) {
+		wsID, err := m.ResolveWorkspace(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		return svc.GetWorkspace(ctx, wsID)
+	}
+
+	items, err := m.bulk.items(args)
+	if err != nil {
+		return err
+	}
+	if !m.bulk.single(items) {
+		return runBulk(cmd.Context(), m.Utils, &m.bulk, items, get)
+	}
+
+	data, err := get(cmd.Context(), items[0])
 	if err != nil {
 		return err
 	}
@@ -114,13 +142,34 @@ func (m *WorkspaceManager) workspaceGet(cmd *cobra.Command, args []string) error
 }
 
 func (m *WorkspaceManager) workspaceDelete(cmd *cobra.Command, args []string) error {
-	data, err := m.GetAPIClient().DeleteWorkspace(args[0])
+	svc, err := m.GetWorkspaceService()
+	if err != nil {
+		return err
+	}
+
+	del := func(ctx context.Context, ref string) (*repoflow.Workspace, error) {
+		wsID, err := m.ResolveWorkspace(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		return svc.DeleteWorkspace(ctx, wsID)
+	}
+
+	items, err := m.bulk.items(args)
+	if err != nil {
+		return err
+	}
+	if !m.bulk.single(items) {
+		return runBulk(cmd.Context(), m.Utils, &m.bulk, items, del)
+	}
+
+	data, err := del(cmd.Context(), items[0])
 	if err != nil {
 		return err
 	}
 
 	if m.Output == "text" || m.Output == "" {
-		fmt.Printf("Successfully deleted workspace '%s'\n", args[0])
+		fmt.Printf("Successfully deleted workspace '%s'\n", items[0])
 		return nil
 	}
 	return factory.HandleOutput(m.Utils, data)
@@ -137,7 +186,12 @@ func (m *WorkspaceManager) workspaceCreate(cmd *cobra.Command, args []string) er
 		Comments:       m.comments,
 	}
 
-	data, err := m.GetAPIClient().CreateWorkspace(opts)
+	svc, err := m.GetWorkspaceService()
+	if err != nil {
+		return err
+	}
+
+	data, err := svc.CreateWorkspace(cmd.Context(), opts)
 
 	if err != nil {
 		return err
@@ -1,10 +1,125 @@
 package factory
 
 import (
+	"fmt"
+
 	"github.com/fe80/go-repoflow/pkg/config"
 	"github.com/fe80/go-repoflow/pkg/repoflow"
 )
 
-func GetClient(cfg *config.Config) *repoflow.Client {
-	return repoflow.NewClient(cfg.URL, cfg.Token)
+// GetClient builds the API client from the configuration.
+// Extra options are applied after the configuration ones.
+func GetClient(cfg *config.Config, opts ...repoflow.Option) (*repoflow.Client, error) {
+	tokenSource, err := getTokenSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []repoflow.Option{
+		repoflow.WithTokenSource(tokenSource),
+		repoflow.WithRetryPolicy(&repoflow.RetryPolicy{
+			MaxAttempts:        cfg.Retry.MaxAttempts,
+			MinBackoff:         cfg.Retry.MinBackoff,
+			MaxBackoff:         cfg.Retry.MaxBackoff,
+			RetryNonIdempotent: cfg.Retry.RetryNonIdempotent,
+		}),
+	}
+	if cfg.RateLimit > 0 {
+		options = append(options, repoflow.WithRateLimit(cfg.RateLimit, cfg.RateBurst))
+	}
+	if cfg.MaxConcurrency > 0 {
+		options = append(options, repoflow.WithMaxConcurrency(cfg.MaxConcurrency))
+	}
+	if cfg.Timeout > 0 {
+		options = append(options, repoflow.WithTimeout(cfg.Timeout))
+	}
+
+	tlsOptions, err := getTLSOptions(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tlsOptions.Config()
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, repoflow.WithTLSConfig(tlsConfig))
+
+	proxy, err := repoflow.ProxyOptions{
+		URL:      cfg.Proxy.URL,
+		Username: cfg.Proxy.Username,
+		Password: cfg.Proxy.Password,
+		NoProxy:  cfg.Proxy.NoProxy,
+	}.Func()
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, repoflow.WithProxy(proxy))
+
+	decodeMode, err := repoflow.ParseDecodeMode(cfg.StrictDecoding)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, repoflow.WithStrictDecoding(decodeMode))
+
+	if cfg.Cache.Enabled {
+		cache, err := GetCache(cfg)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, repoflow.WithCache(cache))
+	}
+
+	return repoflow.NewClient(cfg.URL, append(options, opts...)...), nil
+}
+
+// GetCache returns the response cache described by the configuration
+func GetCache(cfg *config.Config) (*repoflow.Cache, error) {
+	dir := cfg.Cache.Dir
+	if dir == "" {
+		var err error
+		dir, err = repoflow.DefaultCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+		}
+	}
+	return repoflow.NewCache(dir, cfg.Cache.TTL), nil
+}
+
+func getTokenSource(cfg *config.Config) (repoflow.TokenSource, error) {
+	ts := cfg.TokenSource
+
+	switch ts.Type {
+	case "", "static":
+		return repoflow.StaticTokenSource(cfg.Token), nil
+	case "env":
+		if ts.Env == "" {
+			return nil, fmt.Errorf("token_source.env is required for the env token source")
+		}
+		return repoflow.EnvTokenSource(ts.Env), nil
+	case "file":
+		if ts.File == "" {
+			return nil, fmt.Errorf("token_source.file is required for the file token source")
+		}
+		return repoflow.NewFileTokenSource(ts.File), nil
+	case "command":
+		if len(ts.Command) == 0 {
+			return nil, fmt.Errorf("token_source.command is required for the command token source")
+		}
+		return repoflow.NewCommandTokenSource(ts.TTL, ts.Command[0], ts.Command[1:]...), nil
+	}
+	return nil, fmt.Errorf("unsupported token source: %s", ts.Type)
+}
+
+func getTLSOptions(cfg config.TLSConfig) (repoflow.TLSOptions, error) {
+	minVersion, err := repoflow.ParseTLSVersion(cfg.MinVersion)
+	if err != nil {
+		return repoflow.TLSOptions{}, err
+	}
+	return repoflow.TLSOptions{
+		CAFile:             cfg.CAFile,
+		CertFile:           cfg.CertFile,
+		KeyFile:            cfg.KeyFile,
+		MinVersion:         minVersion,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}, nil
 }
@@ -72,13 +72,25 @@ func (u *Utils) TableFormat(out io.Writer, data interface{}) error {
 		return nil
 	}
 
-	itemType := v.Index(0).Type()
+	itemType := v.Type().Elem()
+	if itemType.Kind() == reflect.Ptr {
+		itemType = itemType.Elem()
+	}
 	if itemType.Kind() != reflect.Struct {
 		return fmt.Errorf("TableFormat requires a slice of structs, got slice of %s", itemType.Kind())
 	}
 
 	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	writeTableHeader(w, itemType)
+	for i := 0; i < v.Len(); i++ {
+		writeTableRow(w, v.Index(i))
+	}
+
+	return w.Flush()
+}
 
+// writeTableHeader writes the column names of itemType and their underline
+func writeTableHeader(w io.Writer, itemType reflect.Type) {
 	var headers []string
 	for i := 0; i < itemType.NumField(); i++ {
 		field := itemType.Field(i)
@@ -95,25 +107,95 @@ func (u *Utils) TableFormat(out io.Writer, data interface{}) error {
 		separators = append(separators, strings.Repeat("-", len(h)))
 	}
 	fmt.Fprintln(w, strings.Join(separators, "\t"))
+}
 
-	for i := 0; i < v.Len(); i++ {
-		item := v.Index(i)
-		var row []string
-		for j := 0; j < item.NumField(); j++ {
-			fieldVal := item.Field(j)
-
-			if fieldVal.Kind() == reflect.Ptr {
-				if fieldVal.IsNil() {
-					row = append(row, "<nil>")
-				} else {
-					row = append(row, fmt.Sprintf("%v", fieldVal.Elem().Interface()))
-				}
+// writeTableRow writes the fields of a struct, nil items are skipped
+func writeTableRow(w io.Writer, v reflect.Value) {
+	item := reflect.Indirect(v)
+	if !item.IsValid() {
+		return
+	}
+	var row []string
+	for j := 0; j < item.NumField(); j++ {
+		fieldVal := item.Field(j)
+
+		if fieldVal.Kind() == reflect.Ptr {
+			if fieldVal.IsNil() {
+				row = append(row, "<nil>")
 			} else {
-				row = append(row, fmt.Sprintf("%v", fieldVal.Interface()))
+				row = append(row, fmt.Sprintf("%v", fieldVal.Elem().Interface()))
 			}
+		} else {
+			row = append(row, fmt.Sprintf("%v", fieldVal.Interface()))
 		}
-		fmt.Fprintln(w, strings.Join(row, "\t"))
 	}
+	fmt.Fprintln(w, strings.Join(row, "\t"))
+}
 
-	return w.Flush()
+// streamedRowsBlock is the number of table rows aligned and flushed together
+const streamedRowsBlock = 100
+
+// ItemWriter prints the items of a listing as they are produced, so large
+// listings are never held in memory. Text rows are aligned and flushed by
+// blocks, JSON is written as one object per line and YAML as a stream of
+// documents.
+type ItemWriter struct {
+	out    io.Writer
+	output string
+	table  *tabwriter.Writer
+	rows   int
+}
+
+// NewItemWriter creates an item writer printing to out in the output format
+func (u *Utils) NewItemWriter(out io.Writer) *ItemWriter {
+	return &ItemWriter{out: out, output: u.Output}
+}
+
+// Write prints item, a struct or a pointer to a struct
+func (w *ItemWriter) Write(item any) error {
+	switch w.output {
+	case "yaml":
+		data, err := yaml.Marshal(item)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w.out, "---\n%s", data)
+		return err
+
+	case "json":
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w.out, "%s\n", data)
+		return err
+	}
+
+	v := reflect.ValueOf(item)
+	itemType := reflect.Indirect(v).Type()
+	if itemType.Kind() != reflect.Struct {
+		return fmt.Errorf("ItemWriter requires structs, got %s", itemType.Kind())
+	}
+	if w.table == nil {
+		w.table = tabwriter.NewWriter(w.out, 0, 0, 3, ' ', 0)
+		writeTableHeader(w.table, itemType)
+	}
+	writeTableRow(w.table, v)
+	w.rows++
+	if w.rows%streamedRowsBlock == 0 {
+		return w.table.Flush()
+	}
+	return nil
+}
+
+// Close flushes the pending rows
+func (w *ItemWriter) Close() error {
+	if w.output == "yaml" || w.output == "json" {
+		return nil
+	}
+	if w.table == nil {
+		_, err := fmt.Fprintln(w.out, "No data available.")
+		return err
+	}
+	return w.table.Flush()
 }
@@ -0,0 +1,65 @@
+package factory
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type formatItem struct {
+	Id   string  `json:"id"`
+	Name *string `json:"name"`
+}
+
+func TestItemWriter(t *testing.T) {
+	name := "npm"
+	items := []*formatItem{{Id: "1", Name: &name}, {Id: "2"}}
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"json", "{\"id\":\"1\",\"name\":\"npm\"}\n{\"id\":\"2\",\"name\":null}\n"},
+		{"yaml", "---\nid: \"1\"\nname: npm\n---\nid: \"2\"\nname: null\n"},
+		{"text", "ID   NAME\n--   ----\n1    npm\n2    <nil>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			var out bytes.Buffer
+			w := (&Utils{Output: tt.output}).NewItemWriter(&out)
+			for _, item := range items {
+				if err := w.Write(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestItemWriterFlushesBlocks(t *testing.T) {
+	var out bytes.Buffer
+	w := (&Utils{Output: "text"}).NewItemWriter(&out)
+	for i := range streamedRowsBlock {
+		if out.Len() > 0 {
+			t.Fatalf("rows written after %d items, want after %d", i, streamedRowsBlock)
+		}
+		w.Write(&formatItem{Id: "1"})
+	}
+	if got := strings.Count(out.String(), "\n"); got != streamedRowsBlock+2 {
+		t.Errorf("%d lines written after a block, want %d", got, streamedRowsBlock+2)
+	}
+
+	out.Reset()
+	empty := (&Utils{Output: "text"}).NewItemWriter(&out)
+	empty.Close()
+	if out.String() != "No data available.\n" {
+		t.Errorf("empty output = %q", out.String())
+	}
+}
@@ -2,11 +2,9 @@ package factory
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/fe80/go-repoflow/pkg/repoflow"
 )
@@ -26,7 +24,7 @@ func (u *Utils) HandleResponse(resp *http.Response) error {
 		"method", resp.Request.Method,
 		"url", resp.Request.URL.String(),
 		"status", resp.Status,
-		"payload", string(body),
+		"payload", repoflow.RedactBody(body),
 	)
 
 	resp.Body = io.NopCloser(bytes.NewBuffer(body))
@@ -35,16 +33,6 @@ func (u *Utils) HandleResponse(resp *http.Response) error {
 		return nil
 	}
 
-	var apiErr repoflow.APIError
-	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
-		return fmt.Errorf("API Error (%d): %s - %s", resp.StatusCode, apiErr.Code, apiErr.Message)
-	}
-
-	var apiErrs repoflow.APIErrors
-	if err := json.Unmarshal(body, &apiErrs); err == nil && len(apiErrs.Errors) > 0 {
-		return fmt.Errorf("API Errors (%d): %s", resp.StatusCode, strings.Join(apiErrs.Errors, "; "))
-	}
-
 	// Returning the error stops the command execution and displays the message
-	return fmt.Errorf("request failed with status: %s", resp.Status)
+	return repoflow.CheckResponse(resp)
 }
@@ -1,21 +1,164 @@
 package factory
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/fe80/go-repoflow/pkg/config"
 	"github.com/fe80/go-repoflow/pkg/repoflow"
-	"log/slog"
 )
 
 type Utils struct {
-	Cfg       *config.Config
-	Logger    *slog.Logger
-	Output    string
-	apiClient *repoflow.Client
+	Cfg    *config.Config
+	Logger *slog.Logger
+	Output string
+	// ClientOptions are applied last when building the API client,
+	// for instance to plug a recorder in tests with its Option method
+	ClientOptions []repoflow.Option
+	// Overrides applies the global flags to a configuration, it is also
+	// applied to the profiles returned by Profile
+	Overrides func(cfg *config.Config)
+	// Workspaces, Repositories, Status, Requests and Transfers replace the
+	// API client services when set, for instance with fakes in tests
+	Workspaces   repoflow.WorkspaceService
+	Repositories repoflow.RepositoryService
+	Status       repoflow.StatusService
+	Requests     repoflow.RequestService
+	Transfers    repoflow.TransferService
+	apiClient    *repoflow.Client
+	resolver     *repoflow.Resolver
+}
+
+// Profile returns the utilities of a named configuration profile, for
+// instance to reach another instance, with the global flags applied
+func (u *Utils) Profile(profile string) (*Utils, error) {
+	cfg, err := u.Cfg.LoadProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	if u.Overrides != nil {
+		u.Overrides(cfg)
+	}
+	return &Utils{Cfg: cfg, Logger: u.Logger, Output: u.Output, ClientOptions: u.ClientOptions, Overrides: u.Overrides}, nil
 }
 
-func (u *Utils) GetAPIClient() *repoflow.Client {
+// GetAPIClient returns the API client, building it on first use
+func (u *Utils) GetAPIClient() (*repoflow.Client, error) {
 	if u.apiClient == nil {
-		u.apiClient = GetClient(u.Cfg)
+		opts := []repoflow.Option{repoflow.WithLogger(u.Logger)}
+		opts = append(opts, u.ClientOptions...)
+		// --debug enables the HTTP wire logging
+		if u.Logger.Enabled(context.Background(), slog.LevelDebug) {
+			opts = append(opts, repoflow.WithLogging(u.Logger))
+		}
+		client, err := GetClient(u.Cfg, opts...)
+		if err != nil {
+			return nil, err
+		}
+		u.apiClient = client
+	}
+	return u.apiClient, nil
+}
+
+// GetWorkspaceService returns the workspace API
+func (u *Utils) GetWorkspaceService() (repoflow.WorkspaceService, error) {
+	if u.Workspaces != nil {
+		return u.Workspaces, nil
+	}
+	client, err := u.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Workspaces, nil
+}
+
+// GetRepositoryService returns the repository API
+func (u *Utils) GetRepositoryService() (repoflow.RepositoryService, error) {
+	if u.Repositories != nil {
+		return u.Repositories, nil
+	}
+	client, err := u.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Repositories, nil
+}
+
+// GetStatusService returns the server status API
+func (u *Utils) GetStatusService() (repoflow.StatusService, error) {
+	if u.Status != nil {
+		return u.Status, nil
+	}
+	client, err := u.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Status, nil
+}
+
+// GetRequestService returns the raw request API
+func (u *Utils) GetRequestService() (repoflow.RequestService, error) {
+	if u.Requests != nil {
+		return u.Requests, nil
+	}
+	client, err := u.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Requests, nil
+}
+
+// GetTransferService returns the file transfer API
+func (u *Utils) GetTransferService() (repoflow.TransferService, error) {
+	if u.Transfers != nil {
+		return u.Transfers, nil
+	}
+	client, err := u.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Transfers, nil
+}
+
+// GetResolver returns the name resolver shared by the invocation
+func (u *Utils) GetResolver() (*repoflow.Resolver, error) {
+	if u.resolver == nil {
+		workspaces, err := u.GetWorkspaceService()
+		if err != nil {
+			return nil, err
+		}
+		repositories, err := u.GetRepositoryService()
+		if err != nil {
+			return nil, err
+		}
+		u.resolver = repoflow.NewResolver(workspaces, repositories)
+	}
+	return u.resolver, nil
+}
+
+// ResolveWorkspace returns the ID of the workspace designated by ref (ID or name)
+func (u *Utils) ResolveWorkspace(ctx context.Context, ref string) (string, error) {
+	resolver, err := u.GetResolver()
+	if err != nil {
+		return "", err
+	}
+	ws, err := resolver.ResolveWorkspace(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return ws.Id, nil
+}
+
+// ResolveRepository returns the IDs of the workspace and the repository
+// designated by references (ID or name)
+func (u *Utils) ResolveRepository(ctx context.Context, workspace string, ref string) (string, string, error) {
+	resolver, err := u.GetResolver()
+	if err != nil {
+		return "", "", err
+	}
+	ws, repo, err := resolver.ResolveRepository(ctx, workspace, ref)
+	if err != nil {
+		return "", "", err
 	}
-	return u.apiClient
+	return ws.Id, repo.Id, nil
 }
@@ -0,0 +1,38 @@
+package factory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fe80/go-repoflow/pkg/config"
+)
+
+func TestProfile(t *testing.T) {
+	dir := t.TempDir()
+	profile := "url: https://staging.example.com/api\nretry:\n  max_attempts: 5\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.staging.yaml"), []byte(profile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	u := &Utils{
+		Cfg:    &config.Config{File: filepath.Join(dir, "config.yaml")},
+		Output: "json",
+		Overrides: func(cfg *config.Config) {
+			cfg.TLS.InsecureSkipVerify = true
+		},
+	}
+	staging, err := u.Profile("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staging.Cfg.URL != "https://staging.example.com/api" || staging.Cfg.Retry.MaxAttempts != 5 {
+		t.Errorf("profile configuration = %+v", staging.Cfg)
+	}
+	if !staging.Cfg.TLS.InsecureSkipVerify {
+		t.Error("overrides not applied to the profile")
+	}
+	if staging.Output != "json" || staging.Overrides == nil {
+		t.Errorf("profile utils = %+v", staging)
+	}
+}
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,28 +13,141 @@ import (
 type Config struct {
 	URL   string `mapstructure:"url"`
 	Token string `mapstructure:"token"`
+	// TokenSource choisit la provenance du token (static, env, file, command)
+	TokenSource TokenSourceConfig `mapstructure:"token_source"`
+	Timeout     time.Duration     `mapstructure:"timeout"`
+	Retry       RetryConfig       `mapstructure:"retry"`
+	// Limites côté client, 0 désactive la limite
+	RateLimit      float64     `mapstructure:"rate_limit"`
+	RateBurst      int         `mapstructure:"rate_burst"`
+	MaxConcurrency int         `mapstructure:"max_concurrency"`
+	TLS            TLSConfig   `mapstructure:"tls"`
+	Proxy          ProxyConfig `mapstructure:"proxy"`
+	Cache          CacheConfig `mapstructure:"cache"`
+	// StrictDecoding compare les réponses aux types Go (off, warn, error)
+	StrictDecoding string `mapstructure:"strict_decoding"`
+	// File est le fichier de configuration lu, vide sans fichier
+	File string `mapstructure:"-"`
 }
 
+// TLSConfig définit les paramètres TLS de la connexion à l'API
+type TLSConfig struct {
+	CAFile             string `mapstructure:"ca_file"`
+	CertFile           string `mapstructure:"cert_file"`
+	KeyFile            string `mapstructure:"key_file"`
+	MinVersion         string `mapstructure:"min_version"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// TokenSourceConfig définit la provenance du token personnel.
+// Le type static utilise la clé "token".
+type TokenSourceConfig struct {
+	Type    string        `mapstructure:"type"`
+	Env     string        `mapstructure:"env"`
+	File    string        `mapstructure:"file"`
+	Command []string      `mapstructure:"command"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// RetryConfig définit la politique de retry des requêtes API
+type RetryConfig struct {
+	MaxAttempts        int           `mapstructure:"max_attempts"`
+	MinBackoff         time.Duration `mapstructure:"min_backoff"`
+	MaxBackoff         time.Duration `mapstructure:"max_backoff"`
+	RetryNonIdempotent bool          `mapstructure:"retry_non_idempotent"`
+}
+
+// ProxyConfig définit le proxy HTTP utilisé pour joindre l'API.
+// Sans URL, les variables HTTP(S)_PROXY et NO_PROXY sont utilisées.
+type ProxyConfig struct {
+	URL      string   `mapstructure:"url"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	NoProxy  []string `mapstructure:"no_proxy"`
+}
+
+// CacheConfig définit le cache disque des réponses GET.
+// Sans répertoire, le cache est stocké dans le répertoire cache de l'utilisateur.
+type CacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Dir     string        `mapstructure:"dir"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// searchPaths sont les répertoires où les fichiers de configuration sont cherchés
+var searchPaths = []string{"./configs", "."}
+
 // Load charge la configuration depuis un fichier et/ou l'environnement
 func Load(configPath string) (*Config, error) {
+	return load(configPath, "config", "REPOFLOW", searchPaths)
+}
+
+// LoadProfile charge la configuration d'un profil nommé, par exemple pour
+// joindre une autre instance. Le fichier "config.<profil>" est cherché dans
+// le répertoire de la configuration principale c, puis aux emplacements par
+// défaut. Les variables d'environnement sont préfixées par REPOFLOW_<PROFIL>_.
+func (c *Config) LoadProfile(profile string) (*Config, error) {
+	if profile == "" {
+		return nil, fmt.Errorf("profile name is required")
+	}
+	paths := searchPaths
+	if c.File != "" {
+		paths = append([]string{filepath.Dir(c.File)}, searchPaths...)
+	}
+	envPrefix := "REPOFLOW_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(profile))
+	cfg, err := load("", "config."+profile, envPrefix, paths)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+	return cfg, nil
+}
+
+func load(configPath string, configName string, envPrefix string, paths []string) (*Config, error) {
 	v := viper.New()
 
 	// Configuration par défaut
 	v.SetDefault("url", "https://127.0.0.1/api")
+	v.SetDefault("timeout", time.Minute)
+	v.SetDefault("token_source.type", "static")
+	v.SetDefault("token_source.env", "")
+	v.SetDefault("token_source.file", "")
+	v.SetDefault("token_source.command", []string{})
+	v.SetDefault("token_source.ttl", 0)
+	v.SetDefault("retry.max_attempts", 3)
+	v.SetDefault("retry.min_backoff", 500*time.Millisecond)
+	v.SetDefault("retry.max_backoff", 30*time.Second)
+	v.SetDefault("retry.retry_non_idempotent", false)
+	v.SetDefault("rate_limit", 0)
+	v.SetDefault("rate_burst", 1)
+	v.SetDefault("max_concurrency", 0)
+	v.SetDefault("tls.ca_file", "")
+	v.SetDefault("tls.cert_file", "")
+	v.SetDefault("tls.key_file", "")
+	v.SetDefault("tls.min_version", "1.2")
+	v.SetDefault("tls.insecure_skip_verify", false)
+	v.SetDefault("proxy.url", "")
+	v.SetDefault("proxy.username", "")
+	v.SetDefault("proxy.password", "")
+	v.SetDefault("proxy.no_proxy", []string{})
+	v.SetDefault("cache.enabled", false)
+	v.SetDefault("cache.dir", "")
+	v.SetDefault("cache.ttl", 30*time.Second)
+	v.SetDefault("strict_decoding", "off")
 
 	// Configuration du fichier
 	if configPath != "" {
 		v.SetConfigFile(configPath)
 	} else {
-		v.SetConfigName("config")
-		v.AddConfigPath("./configs")
-		v.AddConfigPath(".")
+		v.SetConfigName(configName)
+		for _, path := range paths {
+			v.AddConfigPath(path)
+		}
 	}
 
 	// Mapping des variables d'environnement
-	v.SetEnvPrefix("REPOFLOW")
+	v.SetEnvPrefix(envPrefix)
 	v.AutomaticEnv()
-	// Permet de mapper REPOFLOW_URL vers la clé "url"
+	// Permet de mapper REPOFLOW_URL vers la clé "url" et REPOFLOW_RETRY_MAX_ATTEMPTS vers "retry.max_attempts"
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Lecture
@@ -46,6 +162,7 @@ func Load(configPath string) (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
+	cfg.File = v.ConfigFileUsed()
 
 	return &cfg, nil
 }
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.yaml", "url: https://main.example.com/api\n")
+	write("config.staging.yaml", "url: https://staging.example.com/api\ntoken: from-file\n")
+	t.Setenv("REPOFLOW_STAGING_TOKEN", "from-env")
+
+	cfg, err := Load(filepath.Join(dir, "main.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.File != filepath.Join(dir, "main.yaml") {
+		t.Errorf("File = %q", cfg.File)
+	}
+
+	// The profile is found next to the main configuration, not in the working directory
+	profile, err := cfg.LoadProfile("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.URL != "https://staging.example.com/api" {
+		t.Errorf("profile URL = %q", profile.URL)
+	}
+	if profile.Token != "from-env" {
+		t.Errorf("profile token = %q, want the environment to win", profile.Token)
+	}
+
+	// A missing profile falls back to the defaults and the environment
+	other, err := cfg.LoadProfile("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.File != "" || other.URL != "https://127.0.0.1/api" {
+		t.Errorf("missing profile = %+v", other)
+	}
+
+	if _, err := cfg.LoadProfile(""); err == nil {
+		t.Error("LoadProfile succeeded without name")
+	}
+}
@@ -0,0 +1,176 @@
+package repoflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultBulkConcurrency is the number of workers used when none is configured
+const DefaultBulkConcurrency = 4
+
+// BulkOptions defines how a bulk operation runs
+type BulkOptions struct {
+	// Concurrency is the number of items processed at once
+	Concurrency int
+	// FailFast stops at the first error, the items not started yet are
+	// skipped and the running ones see their context canceled
+	FailFast bool
+}
+
+// BulkResult is the outcome of the operation on a single item
+type BulkResult[I, T any] struct {
+	Item     I
+	Value    T
+	Err      error
+	Skipped  bool
+	Duration time.Duration
+}
+
+// BulkSummary counts the outcomes of a bulk operation.
+// The duration is encoded in JSON as a string such as "1.5s".
+type BulkSummary struct {
+	Total     int           `json:"total"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// bulkSummaryJSON is the JSON form of BulkSummary
+type bulkSummaryJSON struct {
+	Total     int    `json:"total"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Skipped   int    `json:"skipped"`
+	Duration  string `json:"duration"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (s BulkSummary) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bulkSummaryJSON{
+		Total:     s.Total,
+		Succeeded: s.Succeeded,
+		Failed:    s.Failed,
+		Skipped:   s.Skipped,
+		Duration:  s.Duration.String(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *BulkSummary) UnmarshalJSON(data []byte) error {
+	var v bulkSummaryJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(v.Duration)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	*s = BulkSummary{
+		Total:     v.Total,
+		Succeeded: v.Succeeded,
+		Failed:    v.Failed,
+		Skipped:   v.Skipped,
+		Duration:  duration,
+	}
+	return nil
+}
+
+// BulkReport holds the results of a bulk operation, in the order of the items
+type BulkReport[I, T any] struct {
+	Results []BulkResult[I, T]
+	Summary BulkSummary
+}
+
+// BulkError is returned by BulkReport.Err when some items failed
+type BulkError struct {
+	Summary BulkSummary
+	Errs    []error
+}
+
+// Error implements error
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d of %d item(s) failed", e.Summary.Failed, e.Summary.Total)
+}
+
+// Unwrap exposes the item errors to errors.Is and errors.As
+func (e *BulkError) Unwrap() []error {
+	return e.Errs
+}
+
+// Bulk runs fn on every item with a bounded pool of workers.
+// Cancelling ctx skips the remaining items.
+func Bulk[I, T any](ctx context.Context, items []I, opts BulkOptions, fn func(ctx context.Context, item I) (T, error)) *BulkReport[I, T] {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultBulkConcurrency
+	}
+	workers = min(workers, len(items))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	results := make([]BulkResult[I, T], len(items))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := &results[i]
+				result.Item = items[i]
+				if ctx.Err() != nil {
+					result.Skipped = true
+					continue
+				}
+
+				itemStart := time.Now()
+				result.Value, result.Err = fn(ctx, items[i])
+				result.Duration = time.Since(itemStart)
+				if result.Err != nil && opts.FailFast {
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	report := &BulkReport[I, T]{Results: results}
+	report.Summary.Total = len(items)
+	report.Summary.Duration = time.Since(start)
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			report.Summary.Skipped++
+		case result.Err != nil:
+			report.Summary.Failed++
+		default:
+			report.Summary.Succeeded++
+		}
+	}
+	return report
+}
+
+// Err returns a *BulkError wrapping the item errors, nil when all succeeded
+func (r *BulkReport[I, T]) Err() error {
+	if r.Summary.Failed == 0 {
+		return nil
+	}
+	errs := make([]error, 0, r.Summary.Failed)
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return &BulkError{Summary: r.Summary, Errs: errs}
+}
@@ -0,0 +1,223 @@
+package repoflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+// deleteService deletes the repositories whose ID starts with "ok", the
+// others do not exist
+func deleteService(calls *atomic.Int32) *repoflowtest.FakeRepositoryService {
+	return &repoflowtest.FakeRepositoryService{
+		DeleteRepositoryFunc: func(ctx context.Context, workspace string, id string) (*repoflow.RepostotryDelete, error) {
+			calls.Add(1)
+			if !strings.HasPrefix(id, "ok") {
+				return nil, &repoflow.Error{StatusCode: http.StatusNotFound}
+			}
+			return &repoflow.RepostotryDelete{RepositoryId: id}, nil
+		},
+	}
+}
+
+func TestBulk(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		items      []string
+		opts       repoflow.BulkOptions
+		want       []string
+		wantCalls  int32
+		wantResult repoflow.BulkSummary
+	}{
+		{
+			name:       "all succeed",
+			items:      []string{"ok-1", "ok-2", "ok-3"},
+			want:       []string{"ok", "ok", "ok"},
+			wantCalls:  3,
+			wantResult: repoflow.BulkSummary{Total: 3, Succeeded: 3},
+		},
+		{
+			name:       "errors do not stop the others",
+			items:      []string{"ok-1", "bad-2", "ok-3", "bad-4"},
+			opts:       repoflow.BulkOptions{Concurrency: 2},
+			want:       []string{"ok", "failed", "ok", "failed"},
+			wantCalls:  4,
+			wantResult: repoflow.BulkSummary{Total: 4, Succeeded: 2, Failed: 2},
+		},
+		{
+			name:       "fail fast skips the remaining items",
+			items:      []string{"ok-1", "bad-2", "ok-3", "ok-4"},
+			opts:       repoflow.BulkOptions{Concurrency: 1, FailFast: true},
+			want:       []string{"ok", "failed", "skipped", "skipped"},
+			wantCalls:  2,
+			wantResult: repoflow.BulkSummary{Total: 4, Succeeded: 1, Failed: 1, Skipped: 2},
+		},
+		{
+			name:       "canceled context skips every item",
+			ctx:        canceled,
+			items:      []string{"ok-1", "ok-2"},
+			want:       []string{"skipped", "skipped"},
+			wantResult: repoflow.BulkSummary{Total: 2, Skipped: 2},
+		},
+		{
+			name:       "no items",
+			items:      nil,
+			want:       []string{},
+			wantResult: repoflow.BulkSummary{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			var calls atomic.Int32
+			svc := deleteService(&calls)
+
+			report := repoflow.Bulk(ctx, tt.items, tt.opts, func(ctx context.Context, id string) (*repoflow.RepostotryDelete, error) {
+				return svc.DeleteRepository(ctx, "ws-1", id)
+			})
+
+			got := make([]string, len(report.Results))
+			for i, result := range report.Results {
+				if result.Item != tt.items[i] {
+					t.Errorf("result %d is for %q, want %q", i, result.Item, tt.items[i])
+				}
+				switch {
+				case result.Skipped:
+					got[i] = "skipped"
+				case result.Err != nil:
+					got[i] = "failed"
+				default:
+					got[i] = "ok"
+					if result.Value.RepositoryId != result.Item {
+						t.Errorf("result %d value = %+v", i, result.Value)
+					}
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("statuses = %v, want %v", got, tt.want)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls.Load(), tt.wantCalls)
+			}
+
+			summary := report.Summary
+			summary.Duration = 0
+			if summary != tt.wantResult {
+				t.Errorf("summary = %+v, want %+v", summary, tt.wantResult)
+			}
+
+			err := report.Err()
+			var bulkErr *repoflow.BulkError
+			switch {
+			case tt.wantResult.Failed == 0 && err != nil:
+				t.Errorf("Err() = %v, want nil", err)
+			case tt.wantResult.Failed > 0 && (!errors.As(err, &bulkErr) || len(bulkErr.Errs) != tt.wantResult.Failed || !errors.Is(err, repoflow.ErrNotFound)):
+				t.Errorf("Err() = %v, want a BulkError with %d not found errors", err, tt.wantResult.Failed)
+			}
+		})
+	}
+}
+
+func TestBulkKeepsItemOrder(t *testing.T) {
+	// Each item waits for the next one, so they complete in reverse order
+	items := []string{"a", "b", "c", "d", "e"}
+	done := map[string]chan struct{}{}
+	next := map[string]string{}
+	for i, item := range items {
+		done[item] = make(chan struct{})
+		if i+1 < len(items) {
+			next[item] = items[i+1]
+		}
+	}
+	report := repoflow.Bulk(context.Background(), items, repoflow.BulkOptions{Concurrency: len(items)}, func(ctx context.Context, item string) (string, error) {
+		if next, ok := next[item]; ok {
+			<-done[next]
+		}
+		close(done[item])
+		return strings.ToUpper(item), nil
+	})
+
+	for i, result := range report.Results {
+		if result.Item != items[i] || result.Value != strings.ToUpper(items[i]) {
+			t.Errorf("result %d = %+v, want %s", i, result, items[i])
+		}
+	}
+}
+
+func TestBulkConcurrencyLimit(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	items := make([]int, 20)
+
+	repoflow.Bulk(context.Background(), items, repoflow.BulkOptions{Concurrency: 3}, func(ctx context.Context, item int) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			max := maxRunning.Load()
+			if n <= max || maxRunning.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return item, nil
+	})
+
+	if got := maxRunning.Load(); got > 3 {
+		t.Errorf("items run at once = %d, want at most 3", got)
+	}
+}
+
+func TestBulkFailFastCancelsRunningItems(t *testing.T) {
+	started := make(chan struct{})
+	report := repoflow.Bulk(context.Background(), []string{"slow", "bad"}, repoflow.BulkOptions{Concurrency: 2, FailFast: true}, func(ctx context.Context, item string) (string, error) {
+		if item == "slow" {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		<-started
+		return "", errors.New("boom")
+	})
+
+	if err := report.Results[0].Err; !errors.Is(err, context.Canceled) {
+		t.Errorf("running item error = %v, want context.Canceled", err)
+	}
+	if report.Summary.Failed != 2 {
+		t.Errorf("summary = %+v, want 2 failed", report.Summary)
+	}
+}
+
+func TestBulkSummaryJSON(t *testing.T) {
+	summary := repoflow.BulkSummary{Total: 3, Succeeded: 2, Failed: 1, Duration: 1500 * time.Millisecond}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"total":3,"succeeded":2,"failed":1,"skipped":0,"duration":"1.5s"}`; string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+
+	var decoded repoflow.BulkSummary
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != summary {
+		t.Errorf("decoded = %+v, %v, want %+v", decoded, err, summary)
+	}
+	if err := json.Unmarshal([]byte(`{"duration":1500}`), &decoded); err == nil {
+		t.Error("decoding a numeric duration succeeded")
+	}
+}
@@ -0,0 +1,383 @@
+package repoflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultCacheTTL is the freshness of cached responses without validators
+const DefaultCacheTTL = 30 * time.Second
+
+// Cache stores GET responses on disk.
+// Responses carrying an ETag or Last-Modified header are revalidated with a
+// conditional request, the others are served until their TTL expires.
+// Successful mutating requests invalidate the entries under the parent path,
+// or under the repository collection of the workspace for its descendants.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+}
+
+// cacheEntry is the on-disk representation of a cached response
+type cacheEntry struct {
+	URL        string      `json:"url"`
+	StoredAt   time.Time   `json:"storedAt"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// DefaultCacheDir returns the cache directory under the user cache dir
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "repoflow"), nil
+}
+
+// NewCache creates a cache stored in dir, ttl <= 0 uses DefaultCacheTTL
+func NewCache(dir string, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{Dir: dir, TTL: ttl}
+}
+
+// WithCache enables the response cache.
+// Cache hits never consume rate limit tokens or concurrency slots.
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// noCacheKey marks the contexts whose requests ask for a fresh response
+type noCacheKey struct{}
+
+// WithNoCache returns a context whose API requests are sent with
+// Cache-Control: no-cache, bypassing the response cache and any cache between
+// the client and the server
+func WithNoCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// isNoCache reports whether ctx was returned by WithNoCache
+func isNoCache(ctx context.Context) bool {
+	noCache, _ := ctx.Value(noCacheKey{}).(bool)
+	return noCache
+}
+
+// cacheSubdir is the subdirectory of Dir owned by the cache, Dir itself may
+// be shared with other files
+const cacheSubdir = "responses"
+
+// Clear removes every cached response.
+// Only the entries written by the cache are removed, other files are kept.
+func (ca *Cache) Clear() error {
+	root := filepath.Join(ca.Dir, cacheSubdir)
+	servers, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	for _, server := range servers {
+		if !server.IsDir() || !isHashKey(server.Name()) {
+			continue
+		}
+		dir := filepath.Join(root, server.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if f.Type().IsRegular() && isEntryFile(f.Name()) {
+				if err := os.Remove(filepath.Join(dir, f.Name())); err != nil {
+					return err
+				}
+			}
+		}
+		// Directories still holding foreign files are kept
+		os.Remove(dir)
+	}
+	os.Remove(root)
+	return nil
+}
+
+// Middleware returns the caching transport
+func (ca *Cache) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &cacheTransport{next: next, cache: ca}
+	}
+}
+
+// cacheTransport serves GET requests from the cache
+type cacheTransport struct {
+	next  http.RoundTripper
+	cache *Cache
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 && req.Method != http.MethodHead {
+			t.cache.invalidate(req.URL)
+		}
+		return resp, err
+	}
+
+	// Only API documents are cached: streamed downloads, partial downloads
+	// and requests asking for a fresh response go straight to the server
+	if isStreaming(req) || !acceptsJSON(req) || req.Header.Get("Range") != "" ||
+		strings.Contains(req.Header.Get("Cache-Control"), "no-cache") {
+		return t.next.RoundTrip(req)
+	}
+
+	file := t.cache.entryPath(req)
+	entry, _ := t.cache.load(file)
+
+	validated := entry != nil && (entry.Header.Get("ETag") != "" || entry.Header.Get("Last-Modified") != "")
+	if entry != nil && !validated && time.Since(entry.StoredAt) < t.cache.TTL {
+		return entry.response(req), nil
+	}
+
+	if validated {
+		req = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		t.cache.store(file, entry)
+		return entry.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || !cacheable(resp) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.cache.store(file, &cacheEntry{
+		URL:        req.URL.String(),
+		StoredAt:   time.Now(),
+		StatusCode: resp.StatusCode,
+		Header:     storedHeader(resp.Header),
+		Body:       body,
+	})
+	return resp, nil
+}
+
+// acceptsJSON reports whether req asks for an API document
+func acceptsJSON(req *http.Request) bool {
+	accept := req.Header.Get("Accept")
+	return accept != "" && strings.Contains(accept, "json") && !strings.Contains(accept, "*/*")
+}
+
+// cacheable reports whether a response can be stored
+func cacheable(resp *http.Response) bool {
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	return strings.Contains(resp.Header.Get("Content-Type"), "json")
+}
+
+// serverDir returns the directory holding the entries of a server
+func (ca *Cache) serverDir(u *url.URL) string {
+	return filepath.Join(ca.Dir, cacheSubdir, hashKey(u.Scheme+"://"+u.Host))
+}
+
+// entryPath returns the file of a request entry.
+// The credentials are part of the key so users never share entries.
+func (ca *Cache) entryPath(req *http.Request) string {
+	key := hashKey(req.Header.Get("Authorization") + "\n" + req.URL.String())
+	return filepath.Join(ca.serverDir(req.URL), key+".json")
+}
+
+func (ca *Cache) load(file string) (*cacheEntry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// store writes the entry atomically, errors only disable caching
+func (ca *Cache) store(file string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// invalidate removes the entries a mutation of u may have made stale
+func (ca *Cache) invalidate(u *url.URL) error {
+	prefix := invalidationPrefix(u.Path)
+
+	dir := ca.serverDir(u)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	for _, f := range files {
+		file := filepath.Join(dir, f.Name())
+		entry, err := ca.load(file)
+		if err != nil {
+			os.Remove(file)
+			continue
+		}
+		entryURL, err := url.Parse(entry.URL)
+		if err != nil || underPath(entryURL.Path, prefix) {
+			os.Remove(file)
+		}
+	}
+	return nil
+}
+
+// invalidationPrefix returns the path whose entries are invalidated by a
+// mutation of p: its parent, or the repository collection of the workspace
+// when p is below it, since a repository is also fetched and listed without
+// its store
+func invalidationPrefix(p string) string {
+	p = strings.TrimSuffix(p, "/")
+	if collection, _, ok := strings.Cut(p, RepositoryEndpoint+"/"); ok {
+		return collection + RepositoryEndpoint
+	}
+	return path.Dir(p)
+}
+
+// underPath reports whether p is prefix or one of its descendants,
+// comparing whole path segments
+func underPath(p, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// unstoredHeaders are never written to the cache: hop-by-hop headers only
+// apply to the connection they were received on, cookies are per session
+var unstoredHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+	"Set-Cookie",
+	"Set-Cookie2",
+}
+
+// storedHeader returns a copy of header without the headers listed by
+// unstoredHeaders or by its Connection header
+func storedHeader(header http.Header) http.Header {
+	stored := header.Clone()
+	for _, value := range header.Values("Connection") {
+		for _, name := range strings.Split(value, ",") {
+			stored.Del(strings.TrimSpace(name))
+		}
+	}
+	for _, name := range unstoredHeaders {
+		stored.Del(name)
+	}
+	return stored
+}
+
+// response builds the HTTP response served from the entry
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func hashKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// isHashKey reports whether name was produced by hashKey
+func isHashKey(name string) bool {
+	if len(name) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
+
+// isEntryFile reports whether name is an entry, or a temporary one left by
+// an interrupted store
+func isEntryFile(name string) bool {
+	if strings.HasPrefix(name, ".entry-") {
+		return true
+	}
+	key, ok := strings.CutSuffix(name, ".json")
+	return ok && isHashKey(key)
+}
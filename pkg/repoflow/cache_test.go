@@ -0,0 +1,397 @@
+package repoflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cacheServer counts the requests reaching the server and the ones
+// answered with 304
+type cacheServer struct {
+	*httptest.Server
+	hits        atomic.Int32
+	notModified atomic.Int32
+	version     atomic.Int32
+}
+
+func newCacheServer(t *testing.T, validators bool) *cacheServer {
+	s := &cacheServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.hits.Add(1)
+		etag := fmt.Sprintf(`"v%d"`, s.version.Load())
+		if validators {
+			if r.Header.Get("If-None-Match") == etag {
+				s.notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"version":`+etag+`}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func getVersion(t *testing.T, client *Client, path string) string {
+	t.Helper()
+	var result struct {
+		Version string `json:"version"`
+	}
+	if err := client.DoRequest(context.Background(), http.MethodGet, path, nil, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result.Version
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	srv := newCacheServer(t, true)
+	client := NewClient(srv.URL, WithCache(NewCache(t.TempDir(), time.Hour)))
+
+	for i := range 3 {
+		if got := getVersion(t, client, "/1/workspaces"); got != "v0" {
+			t.Fatalf("request %d: version = %s", i, got)
+		}
+	}
+	if got := srv.notModified.Load(); got != 2 {
+		t.Errorf("304 responses = %d, want 2", got)
+	}
+
+	srv.version.Store(1)
+	if got := getVersion(t, client, "/1/workspaces"); got != "v1" {
+		t.Errorf("version after change = %s, want v1", got)
+	}
+}
+
+func TestCacheRevalidatesWithLastModified(t *testing.T) {
+	lastModified := time.Now().UTC().Format(http.TimeFormat)
+	var conditional atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"version":"v0"}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithCache(NewCache(t.TempDir(), time.Hour)))
+	getVersion(t, client, "/1/workspaces")
+	if got := getVersion(t, client, "/1/workspaces"); got != "v0" {
+		t.Errorf("cached version = %s", got)
+	}
+	if got := conditional.Load(); got != 1 {
+		t.Errorf("conditional requests = %d, want 1", got)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	srv := newCacheServer(t, false)
+	client := NewClient(srv.URL, WithCache(NewCache(t.TempDir(), 100*time.Millisecond)))
+
+	getVersion(t, client, "/1/workspaces")
+	srv.version.Store(1)
+	if got := getVersion(t, client, "/1/workspaces"); got != "v0" {
+		t.Errorf("fresh entry: version = %s, want the cached v0", got)
+	}
+	if got := srv.hits.Load(); got != 1 {
+		t.Errorf("hits = %d, want 1", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if got := getVersion(t, client, "/1/workspaces"); got != "v1" {
+		t.Errorf("expired entry: version = %s, want v1", got)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		status   int
+		wantHits int32
+	}{
+		{"create in collection", http.MethodPost, "/1/workspaces/ws-1/repositories/local", http.StatusOK, 3},
+		{"delete in collection", http.MethodDelete, "/1/workspaces/ws-1/repositories/repo-1", http.StatusOK, 3},
+		{"failed mutation", http.MethodDelete, "/1/workspaces/ws-1/repositories/repo-1", http.StatusNotFound, 2},
+		{"unrelated path", http.MethodDelete, "/1/other/thing", http.StatusOK, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					w.WriteHeader(tt.status)
+					return
+				}
+				hits.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, `{}`)
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL, WithCache(NewCache(t.TempDir(), time.Hour)))
+			ctx := context.Background()
+			client.DoRequest(ctx, http.MethodGet, "/1/workspaces/ws-1/repositories", nil, nil)
+			client.DoRequest(ctx, http.MethodGet, "/1/workspaces/ws-1/repositories/repo-1", nil, nil)
+			client.DoRequest(ctx, tt.method, tt.path, nil, nil)
+			client.DoRequest(ctx, http.MethodGet, "/1/workspaces/ws-1/repositories", nil, nil)
+
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestCacheBypass(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		stream bool
+	}{
+		{"no-cache", http.Header{"Cache-Control": {"no-cache"}}, false},
+		{"range", http.Header{"Range": {"bytes=10-"}}, false},
+		{"artifact", http.Header{"Accept": {"*/*"}}, false},
+		{"streaming", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newCacheServer(t, false)
+			dir := t.TempDir()
+			transport := NewCache(dir, time.Hour).Middleware()(http.DefaultTransport)
+
+			for range 2 {
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/file.json", nil)
+				req.Header.Set("Accept", "application/json")
+				for key, values := range tt.header {
+					req.Header[key] = values
+				}
+				if tt.stream {
+					req = withStreaming(req)
+				}
+				resp, err := transport.RoundTrip(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				drain(resp)
+			}
+
+			if got := srv.hits.Load(); got != 2 {
+				t.Errorf("server hits = %d, want 2", got)
+			}
+			if _, err := os.Stat(filepath.Join(dir, cacheSubdir)); !os.IsNotExist(err) {
+				t.Errorf("cache directory created: %v", err)
+			}
+		})
+	}
+}
+
+func TestCacheClearKeepsForeignFiles(t *testing.T) {
+	srv := newCacheServer(t, false)
+	dir := t.TempDir()
+	cache := NewCache(dir, time.Hour)
+	client := NewClient(srv.URL, WithCache(cache))
+	getVersion(t, client, "/1/workspaces")
+
+	foreign := []string{
+		filepath.Join(dir, "notes.txt"),
+		filepath.Join(dir, cacheSubdir, "notes.txt"),
+		filepath.Join(cache.serverDir(mustParseURL(t, srv.URL)), "notes.txt"),
+	}
+	for _, file := range foreign {
+		if err := os.WriteFile(file, []byte("keep"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range foreign {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("foreign file removed: %v", err)
+		}
+	}
+	entries, _ := filepath.Glob(filepath.Join(dir, cacheSubdir, "*", "*.json"))
+	if len(entries) != 0 {
+		t.Errorf("entries left after Clear: %v", entries)
+	}
+
+	getVersion(t, client, "/1/workspaces")
+	if got := srv.hits.Load(); got != 2 {
+		t.Errorf("server hits after Clear = %d, want 2", got)
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestCacheInvalidationStopsAtSegments(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hits.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithCache(NewCache(t.TempDir(), time.Hour)))
+	ctx := context.Background()
+	for _, path := range []string{"/1/workspaces/a/repositories", "/1/workspaces/ab/repositories"} {
+		client.DoRequest(ctx, http.MethodGet, path, nil, nil)
+	}
+	// Invalidates the entries under /1/workspaces/a
+	client.DoRequest(ctx, http.MethodDelete, "/1/workspaces/a/repositories", nil, nil)
+
+	hits.Store(0)
+	client.DoRequest(ctx, http.MethodGet, "/1/workspaces/ab/repositories", nil, nil)
+	if got := hits.Load(); got != 0 {
+		t.Errorf("sibling entry invalidated, hits = %d", got)
+	}
+	client.DoRequest(ctx, http.MethodGet, "/1/workspaces/a/repositories", nil, nil)
+	if got := hits.Load(); got != 1 {
+		t.Errorf("invalidated entry served from cache, hits = %d", got)
+	}
+}
+
+func TestUnderPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		prefix string
+		want   bool
+	}{
+		{"/1/workspaces/a", "/1/workspaces/a", true},
+		{"/1/workspaces/a/repositories", "/1/workspaces/a", true},
+		{"/1/workspaces/ab", "/1/workspaces/a", false},
+		{"/1/workspaces", "/1/workspaces/a", false},
+		{"/1/workspaces/a", "/1/workspaces/", true},
+		{"/1/workspaces", "/", true},
+	}
+	for _, tt := range tests {
+		if got := underPath(tt.path, tt.prefix); got != tt.want {
+			t.Errorf("underPath(%q, %q) = %v, want %v", tt.path, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestCacheUpdateInvalidatesRepositories(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hits.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithCache(NewCache(t.TempDir(), time.Hour)))
+	ctx := context.Background()
+	cached := []string{
+		"/1/workspaces/a/repositories",
+		"/1/workspaces/a/repositories/r1",
+		"/1/workspaces/a/repositories/r1/packages",
+	}
+	for _, path := range append(cached, "/1/workspaces/b/repositories") {
+		client.DoRequest(ctx, http.MethodGet, path, nil, nil)
+	}
+
+	if _, err := client.UpdateLocalRepository(ctx, "a", "r1", RepositoryUpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	hits.Store(0)
+	for _, path := range cached {
+		client.DoRequest(ctx, http.MethodGet, path, nil, nil)
+	}
+	if got := hits.Load(); got != int32(len(cached)) {
+		t.Errorf("server hits after update = %d, want %d", got, len(cached))
+	}
+	client.DoRequest(ctx, http.MethodGet, "/1/workspaces/b/repositories", nil, nil)
+	if got := hits.Load(); got != int32(len(cached)) {
+		t.Errorf("other workspace invalidated, hits = %d", got)
+	}
+}
+
+func TestInvalidationPrefix(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/1/workspaces/a", "/1/workspaces"},
+		{"/1/workspaces/a/repositories", "/1/workspaces/a"},
+		{"/1/workspaces/a/repositories/local", "/1/workspaces/a/repositories"},
+		{"/1/workspaces/a/repositories/local/r1", "/1/workspaces/a/repositories"},
+		{"/1/workspaces/a/repositories/r1/packages/p1/", "/1/workspaces/a/repositories"},
+	}
+	for _, tt := range tests {
+		if got := invalidationPrefix(tt.path); got != tt.want {
+			t.Errorf("invalidationPrefix(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCacheDropsConnectionHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Connection", "X-Hop")
+		w.Header().Set("X-Hop", "hop")
+		w.Header().Set("Keep-Alive", "timeout=5")
+		w.Header().Set("X-Request-Id", "42")
+		io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	cache := NewCache(t.TempDir(), time.Hour)
+	client := NewClient(srv.URL, WithCache(cache))
+	resp, err := client.DoRequestWithResponse(context.Background(), http.MethodGet, "/1/workspaces", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.Get("Set-Cookie") == "" {
+		t.Error("live response lost its cookie")
+	}
+
+	files, _ := filepath.Glob(filepath.Join(cache.Dir, "*", "*", "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("entries = %v, want 1", files)
+	}
+	entry, err := cache.load(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Set-Cookie", "Connection", "X-Hop", "Keep-Alive"} {
+		if value := entry.Header.Get(name); value != "" {
+			t.Errorf("stored %s: %s", name, value)
+		}
+	}
+	if entry.Header.Get("X-Request-Id") != "42" || entry.Header.Get("Content-Type") == "" {
+		t.Errorf("stored header = %v, want the end-to-end headers", entry.Header)
+	}
+}
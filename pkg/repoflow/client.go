@@ -2,102 +2,304 @@ package repoflow
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
 
+// DefaultTimeout is the HTTP client timeout used when none is configured
+const DefaultTimeout = time.Minute
+
 type Client struct {
-	BaseURL    string
-	Token      string
-	HTTPClient *http.Client
-}
+	BaseURL     string
+	Token       string
+	TokenSource TokenSource
+	UserAgent   string
+	Header      http.Header
+	HTTPClient  *http.Client
+	RetryPolicy *RetryPolicy
+	Logger      *slog.Logger
 
-type APIError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
+	// The services expose the API split by concern, they are all backed
+	// by the client itself
+	Workspaces   WorkspaceService
+	Repositories RepositoryService
+	Status       StatusService
+	Requests     RequestService
+	Transfers    TransferService
 
-type APIErrors struct {
-	Errors []string `json:"errors"`
+	timeout          *time.Duration
+	middlewares      []Middleware
+	outerMiddlewares []Middleware
+	limiter          *RateLimiter
+	slots            chan struct{}
+	tlsConfig        *tls.Config
+	proxy            func(*http.Request) (*url.URL, error)
+	cache            *Cache
+	decodeMode       DecodeMode
 }
 
-func NewClient(baseUrl string, token string) *Client {
-	return &Client{
-		BaseURL: baseUrl,
-		Token:   token,
-		HTTPClient: &http.Client{
-			Timeout: time.Minute,
-		},
+// NewClient creates a client for the RepoFlow API at baseURL
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:     baseURL,
+		UserAgent:   DefaultUserAgent,
+		Header:      make(http.Header),
+		RetryPolicy: DefaultRetryPolicy(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.buildHTTPClient()
+	c.Workspaces = c
+	c.Repositories = c
+	c.Status = c
+	c.Requests = c
+	c.Transfers = c
+	return c
 }
 
-func (c *Client) setHeaders(req *http.Request) {
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-}
-
-func (e *APIErrors) Error() string {
-	if len(e.Errors) == 0 {
-		return "unknown api error"
-	}
-	return fmt.Sprintf("api error: %v", strings.Join(e.Errors, "; "))
+// DoRequest sends an API request and decodes the JSON response into result.
+// The request is bound to ctx, so cancelling it or reaching its deadline
+// aborts the call. Failed attempts are retried according to RetryPolicy.
+func (c *Client) DoRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	_, err := c.DoRequestWithResponse(ctx, method, path, body, result)
+	return err
 }
 
-func (c *Client) DoRequest(method, path string, body interface{}, result interface{}) error {
-	var bodyReader io.Reader
+// DoRequestWithResponse is DoRequest also returning the response metadata.
+// The response is returned along with API errors, it is nil when no
+// response was received.
+func (c *Client) DoRequestWithResponse(ctx context.Context, method, path string, body interface{}, result interface{}) (*Response, error) {
+	var jsonBody []byte
 
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
 	}
 
 	url := fmt.Sprintf("%s%s", c.BaseURL, path)
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := c.newRequest(ctx, method, url, jsonBody)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/json")
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	start := time.Now()
+	resp, err := c.do(c.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	response := newResponse(resp, time.Since(start))
+
+	if err := CheckResponse(resp); err != nil {
+		return response, err
 	}
-	if c.Token != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		return response, c.decode(resp, method, path, result)
+	}
+
+	return response, nil
+}
+
+// decode decodes the JSON response into result, comparing it with the type
+// of result unless the decoding mode is lenient
+func (c *Client) decode(resp *http.Response, method, path string, result interface{}) error {
+	if c.decodeMode == DecodeLenient {
+		// An empty body leaves result untouched
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
 	}
-	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	diff, err := CompareSchema(data, result)
+	if err != nil || diff.Empty() {
+		return nil
+	}
+	if c.decodeMode == DecodeStrict {
+		return &SchemaError{Method: method, Path: path, Diff: diff}
+	}
+	c.logger().Warn("Response does not match its schema",
+		"method", method,
+		"path", path,
+		"unknown", diff.Unknown,
+		"missing", diff.Missing,
+	)
+	return nil
+}
+
+// newRequest builds the API request.
+// The body is backed by a bytes.Reader so it can be replayed across retries.
+func (c *Client) newRequest(ctx context.Context, method, url string, jsonBody []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if jsonBody != nil {
+		bodyReader = bytes.NewReader(jsonBody)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		var errs APIErrors
-		bodyBytes, _ := io.ReadAll(resp.Body)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-		if len(bodyBytes) > 0 {
-			if err := json.Unmarshal(bodyBytes, &errs); err == nil && len(errs.Errors) > 0 {
-				return &errs
+	// The client headers may carry credentials, other origins do not get them
+	if c.sameOrigin(req.URL) {
+		for key, values := range c.Header {
+			for _, value := range values {
+				req.Header.Add(key, value)
 			}
 		}
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+	if isNoCache(ctx) {
+		req.Header.Set("Cache-Control", "no-cache")
+	}
+	if jsonBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
 
-		return fmt.Errorf("api error: status %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
+// do sends req with hc, retrying failed attempts according to RetryPolicy.
+// A 401 response triggers a single token refresh followed by a new round.
+// Streamed bodies, which cannot be replayed, are sent once. Token source
+// failures are returned immediately, retrying would not fix them.
+func (c *Client) do(hc *http.Client, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempts := c.RetryPolicy.attempts(req.Method)
+	refreshed := false
+	if req.Body != nil && req.GetBody == nil {
+		attempts = 1
+		refreshed = true
 	}
 
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	for attempt := 1; ; attempt++ {
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+		resp, err := c.send(hc, req, token)
+
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && !refreshed && c.sameOrigin(req.URL) && c.refreshToken(ctx) {
+			refreshed = true
+			attempt = 0
+			drain(resp)
+			continue
+		}
+		if attempt >= attempts || !c.RetryPolicy.retryable(ctx, resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+			return resp, nil
+		}
+
+		wait := c.RetryPolicy.backoff(attempt, resp)
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+			drain(resp)
+		}
+		c.logger().Debug("Retrying request",
+			"method", req.Method,
+			"url", RedactURL(req.URL),
+			"attempt", attempt,
+			"status", status,
+			"error", err,
+			"wait", wait,
+		)
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
 		}
 	}
+}
 
-	return nil
+// send performs a single HTTP attempt with a fresh copy of req.
+// The token is only sent to the origin of the base URL.
+func (c *Client) send(hc *http.Client, req *http.Request, token string) (*http.Response, error) {
+	attemptReq := req.Clone(req.Context())
+	if token != "" && c.sameOrigin(req.URL) {
+		attemptReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		attemptReq.Body = body
+	}
+	return hc.Do(attemptReq)
+}
+
+// sameOrigin reports whether u has the scheme, host and port of the base URL
+func (c *Client) sameOrigin(u *url.URL) bool {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, base.Scheme) &&
+		strings.EqualFold(u.Hostname(), base.Hostname()) &&
+		urlPort(u) == urlPort(base)
+}
+
+// streamingKey marks the context of the requests streaming their bodies
+type streamingKey struct{}
+
+// withStreaming marks req as streaming its bodies, they are neither
+// buffered by the wire logging nor cached
+func withStreaming(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), streamingKey{}, true))
+}
+
+// isStreaming reports whether req was marked by withStreaming
+func isStreaming(req *http.Request) bool {
+	streaming, _ := req.Context().Value(streamingKey{}).(bool)
+	return streaming
+}
+
+// streamingClient returns the HTTP client used to stream bodies.
+// The overall timeout would cut long transfers, they rely on the context.
+func (c *Client) streamingClient() *http.Client {
+	hc := *c.HTTPClient
+	hc.Timeout = 0
+	return &hc
+}
+
+// drain discards and closes the body so the connection can be reused
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func (c *Client) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return slog.Default()
 }
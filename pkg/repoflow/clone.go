@@ -0,0 +1,135 @@
+package repoflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoCounterpart is returned when a child of a virtual repository has no
+// repository of the same name in the destination workspace
+var ErrNoCounterpart = errors.New("no counterpart in the destination workspace")
+
+// CloneOptions customizes the copy of a repository definition
+type CloneOptions struct {
+	// Name of the copy, the source name by default
+	Name string
+	// RemoteRepositoryUsername replaces the username of a remote repository
+	RemoteRepositoryUsername *string
+	// RemoteRepositoryPassword is the password of a remote repository,
+	// the API never returns it
+	RemoteRepositoryPassword string
+}
+
+// Cloner creates repositories from the definition of existing ones, in the
+// same workspace or in another one, possibly on another instance.
+// Packages are not copied.
+type Cloner struct {
+	repositories RepositoryService
+}
+
+// NewCloner creates a cloner creating the copies with the given service
+func NewCloner(repositories RepositoryService) *Cloner {
+	return &Cloner{repositories: repositories}
+}
+
+// NewCloner creates a cloner creating the copies with the client
+func (c *Client) NewCloner() *Cloner {
+	return NewCloner(c.Repositories)
+}
+
+// Clone creates in workspace a repository of the same store type and settings
+// as src. The children and the upload target of a virtual repository are
+// mapped by name to the repositories of workspace.
+func (c *Cloner) Clone(ctx context.Context, src *Repository, workspace string, opts CloneOptions) (*Repository, error) {
+	name := opts.Name
+	if name == "" {
+		name = src.Name
+	}
+
+	var create any
+	switch src.RepositoryType {
+	case "local":
+		create = RepositoryOptions{Name: name, PackageType: src.PackageType}
+
+	case "remote":
+		remote := RepositoryRemoteOptions{
+			Name:                              name,
+			PackageType:                       src.PackageType,
+			IsRemoteCacheEnabled:              src.IsRemoteCacheEnabled,
+			FileCacheTimeTillRevalidation:     src.FileCacheTimeTillRevalidation,
+			MetadataCacheTimeTillRevalidation: src.MetadataCacheTimeTillRevalidation,
+			RemoteRepositoryPassword:          opts.RemoteRepositoryPassword,
+		}
+		if src.RemoteRepositoryUrl != nil {
+			remote.RemoteRepositoryUrl = *src.RemoteRepositoryUrl
+		}
+		switch {
+		case opts.RemoteRepositoryUsername != nil:
+			remote.RemoteRepositoryUsername = *opts.RemoteRepositoryUsername
+		case src.RemoteRepositoryUsername != nil:
+			remote.RemoteRepositoryUsername = *src.RemoteRepositoryUsername
+		}
+		create = remote
+
+	case "virtual":
+		virtual, err := c.virtual(ctx, src, workspace, name)
+		if err != nil {
+			return nil, err
+		}
+		create = virtual
+
+	default:
+		return nil, fmt.Errorf("unsupported repository type: %s", src.RepositoryType)
+	}
+
+	return c.repositories.CreateRepository(ctx, workspace, src.RepositoryType, create)
+}
+
+// virtual returns the creation payload of a copy of the virtual repository
+// src, with the children IDs of the destination workspace
+func (c *Cloner) virtual(ctx context.Context, src *Repository, workspace string, name string) (RepositoryVirtualOptions, error) {
+	opts := RepositoryVirtualOptions{Name: name, PackageType: src.PackageType}
+
+	repositories, err := c.repositories.ListRepositories(ctx, workspace)
+	if err != nil {
+		return opts, err
+	}
+	byName := make(map[string]Repositories, len(*repositories))
+	for _, repo := range *repositories {
+		byName[repo.Name] = repo
+	}
+
+	counterpart := func(childName string) (string, error) {
+		repo, ok := byName[childName]
+		if !ok || childName == name {
+			return "", fmt.Errorf("child %s of %s: %w", childName, src.Name, ErrNoCounterpart)
+		}
+		if repo.PackageType != src.PackageType {
+			return "", fmt.Errorf("%w: %s stores %s packages, %s stores %s packages",
+				ErrInvalidMember, repo.Name, repo.PackageType, name, src.PackageType)
+		}
+		return repo.Id, nil
+	}
+
+	opts.ChildRepositoryIds = make([]string, 0, len(src.ChildRepositories))
+	for _, child := range src.ChildRepositories {
+		id, err := counterpart(child.Name)
+		if err != nil {
+			return opts, err
+		}
+		opts.ChildRepositoryIds = append(opts.ChildRepositoryIds, id)
+	}
+
+	if target := uploadTargetOf(src); target != "" {
+		targetName := src.UploadTargetLocalRepository.Name
+		if targetName == "" {
+			targetName = childName(src, target)
+		}
+		if opts.UploadLocalRepositoryId, err = counterpart(targetName); err != nil {
+			return opts, err
+		}
+	}
+
+	return opts, nil
+}
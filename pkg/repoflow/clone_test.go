@@ -0,0 +1,150 @@
+package repoflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+func TestCloner(t *testing.T) {
+	url := "https://registry.npmjs.org"
+	srcUser, dstUser := "reader", "other"
+	ttr := 60
+
+	// The destination workspace holds repositories of the same names with
+	// other IDs
+	destination := []repoflow.Repositories{
+		{Id: "d-local", Name: "npm-local", PackageType: "npm", RepositoryType: "local"},
+		{Id: "d-remote", Name: "npm-remote", PackageType: "npm", RepositoryType: "remote"},
+		{Id: "d-maven", Name: "maven-local", PackageType: "maven", RepositoryType: "local"},
+	}
+	virtual := &repoflow.Repository{
+		Name:                        "npm",
+		PackageType:                 "npm",
+		RepositoryType:              "virtual",
+		ChildRepositories:           []repoflow.ChildRepository{{Id: "s-remote", Name: "npm-remote"}, {Id: "s-local", Name: "npm-local"}},
+		UploadTargetLocalRepository: repoflow.UploadTargetLocalRepository{Id: "s-local"},
+	}
+	withChild := func(name string) *repoflow.Repository {
+		repo := *virtual
+		repo.ChildRepositories = []repoflow.ChildRepository{{Id: "s-" + name, Name: name}}
+		repo.UploadTargetLocalRepository = repoflow.UploadTargetLocalRepository{}
+		return &repo
+	}
+
+	tests := []struct {
+		name      string
+		src       *repoflow.Repository
+		opts      repoflow.CloneOptions
+		wantStore string
+		wantBody  string
+		wantErr   error
+	}{
+		{
+			name:      "local keeps its name by default",
+			src:       &repoflow.Repository{Name: "npm-local", PackageType: "npm", RepositoryType: "local"},
+			wantStore: "local",
+			wantBody:  `{"name":"npm-local","packageType":"npm"}`,
+		},
+		{
+			name: "remote keeps its settings",
+			src: &repoflow.Repository{
+				Name: "npm-remote", PackageType: "npm", RepositoryType: "remote",
+				RemoteRepositoryUrl: &url, RemoteRepositoryUsername: &srcUser,
+				IsRemoteCacheEnabled: true, FileCacheTimeTillRevalidation: &ttr,
+			},
+			opts:      repoflow.CloneOptions{Name: "npm-copy", RemoteRepositoryPassword: "secret"},
+			wantStore: "remote",
+			wantBody: `{"name":"npm-copy","packageType":"npm","remoteRepositoryUrl":"https://registry.npmjs.org",` +
+				`"isRemoteCacheEnabled":true,"remoteRepositoryUsername":"reader","remoteRepositoryPassword":"secret",` +
+				`"fileCacheTimeTillRevalidation":60}`,
+		},
+		{
+			name: "remote with another username",
+			src: &repoflow.Repository{
+				Name: "npm-remote", PackageType: "npm", RepositoryType: "remote",
+				RemoteRepositoryUrl: &url, RemoteRepositoryUsername: &srcUser,
+			},
+			opts:      repoflow.CloneOptions{RemoteRepositoryUsername: &dstUser},
+			wantStore: "remote",
+			wantBody: `{"name":"npm-remote","packageType":"npm","remoteRepositoryUrl":"https://registry.npmjs.org",` +
+				`"isRemoteCacheEnabled":false,"remoteRepositoryUsername":"other"}`,
+		},
+		{
+			name:      "virtual children mapped by name in order",
+			src:       virtual,
+			opts:      repoflow.CloneOptions{Name: "npm-all"},
+			wantStore: "virtual",
+			wantBody:  `{"name":"npm-all","packageType":"npm","childRepositoryIds":["d-remote","d-local"],"uploadLocalRepositoryId":"d-local"}`,
+		},
+		{
+			name:    "virtual child without counterpart",
+			src:     withChild("npm-missing"),
+			opts:    repoflow.CloneOptions{Name: "npm-all"},
+			wantErr: repoflow.ErrNoCounterpart,
+		},
+		{
+			name:    "virtual child of another package type",
+			src:     withChild("maven-local"),
+			opts:    repoflow.CloneOptions{Name: "npm-all"},
+			wantErr: repoflow.ErrInvalidMember,
+		},
+		{
+			name:    "virtual child named as the copy",
+			src:     withChild("npm-local"),
+			opts:    repoflow.CloneOptions{Name: "npm-local"},
+			wantErr: repoflow.ErrNoCounterpart,
+		},
+		{
+			name:    "unsupported type",
+			src:     &repoflow.Repository{Name: "odd", RepositoryType: "proxy"},
+			wantErr: errors.New("unsupported repository type: proxy"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var store, body string
+			svc := &repoflowtest.FakeRepositoryService{
+				ListRepositoriesFunc: func(ctx context.Context, workspace string) (*[]repoflow.Repositories, error) {
+					if workspace != "dst" {
+						t.Errorf("listed workspace = %q, want dst", workspace)
+					}
+					return &destination, nil
+				},
+				CreateRepositoryFunc: func(ctx context.Context, workspace string, s string, opts any) (*repoflow.Repository, error) {
+					if workspace != "dst" {
+						t.Errorf("created in workspace = %q, want dst", workspace)
+					}
+					data, err := json.Marshal(opts)
+					store, body = s, string(data)
+					return &repoflow.Repository{Id: "new", RepositoryType: s, WorkspaceId: workspace}, err
+				},
+			}
+
+			repo, err := repoflow.NewCloner(svc).Clone(context.Background(), tt.src, "dst", tt.opts)
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				if store != "" {
+					t.Errorf("repository created on error: %s", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if store != tt.wantStore || body != tt.wantBody {
+				t.Errorf("created %s %s\nwant %s %s", store, body, tt.wantStore, tt.wantBody)
+			}
+			if repo.Id != "new" {
+				t.Errorf("clone = %+v", repo)
+			}
+		})
+	}
+}
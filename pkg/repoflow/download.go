@@ -0,0 +1,260 @@
+package repoflow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned at the end of a download whose content
+// does not match the expected SHA-256
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// DownloadOptions defines how a file is downloaded
+type DownloadOptions struct {
+	// Offset resumes the download at the given byte with a Range request
+	Offset int64
+	// IfRange is the ETag of the partial content, the server sends the
+	// whole file again when it changed
+	IfRange string
+	// SHA256 is the expected hex digest of the whole file
+	SHA256 string
+	// Verify checks the content against the digest sent by the server when
+	// SHA256 is empty
+	Verify bool
+	// Hash receives the streamed bytes, a resumed download should pass a
+	// hash already fed with the first Offset bytes to verify the whole file
+	Hash hash.Hash
+}
+
+// Download is a file streamed from the server.
+// The body is read straight from the response and must be closed.
+type Download struct {
+	// Offset is the position of the first byte served, 0 when the server
+	// ignored the Range request and sent the whole file
+	Offset int64
+	// ContentLength is the number of bytes to read, -1 when unknown
+	ContentLength int64
+	// Size is the size of the whole file, -1 when unknown
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified string
+	// Digest is the raw digest header sent by the server, if any
+	Digest string
+
+	body     io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+// NewDownload returns a download reading body, whose size and digest are
+// unknown, for instance to implement TransferService in tests
+func NewDownload(body io.ReadCloser) *Download {
+	return &Download{ContentLength: -1, Size: -1, body: body}
+}
+
+// Download streams the file at path, relative to the base URL.
+// A resumed download of a file already complete returns an empty download,
+// reading it verifies the checksum of the whole file.
+// GET /:path
+func (c *Client) Download(ctx context.Context, path string, opts *DownloadOptions) (*Download, error) {
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+	if opts.Offset > 0 && opts.SHA256 != "" && opts.Hash == nil {
+		return nil, fmt.Errorf("verifying a resumed download requires the hash of the first %d bytes", opts.Offset)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.resolveURL(path), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+	req = withStreaming(req)
+	if opts.Offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", opts.Offset))
+		if opts.IfRange != "" {
+			req.Header.Set("If-Range", opts.IfRange)
+		}
+	}
+
+	resp, err := c.do(c.streamingClient(), req)
+	if err != nil {
+		return nil, err
+	}
+	// The range of a complete file cannot be satisfied, the remote size
+	// tells whether the local part is the whole file
+	complete := resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && opts.Offset > 0 &&
+		unsatisfiedRangeSize(resp.Header.Get("Content-Range")) == opts.Offset
+	if !complete {
+		if err := CheckResponse(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	d := &Download{
+		ContentLength: resp.ContentLength,
+		Size:          resp.ContentLength,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ETag:          resp.Header.Get("ETag"),
+		LastModified:  resp.Header.Get("Last-Modified"),
+		Digest:        digestHeader(resp.Header),
+		body:          resp.Body,
+	}
+	switch {
+	case complete:
+		drain(resp)
+		d.Offset, d.Size, d.ContentLength = opts.Offset, opts.Offset, 0
+		d.body = http.NoBody
+	case resp.StatusCode == http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		d.Offset = start
+		d.Size = size
+	}
+
+	d.expected = strings.ToLower(opts.SHA256)
+	if d.expected == "" && opts.Verify {
+		d.expected = d.SHA256()
+	}
+
+	d.hash = opts.Hash
+	if d.hash != nil && d.Offset == 0 && opts.Offset > 0 {
+		// The server ignored the range, the hash restarts with the whole file
+		d.hash.Reset()
+	}
+	if d.hash == nil && d.expected != "" {
+		if d.Offset > 0 {
+			// Part of a file cannot be verified without the hash of its head
+			d.expected = ""
+		} else {
+			d.hash = sha256.New()
+		}
+	}
+
+	return d, nil
+}
+
+// Read implements io.Reader, the checksum is verified at the end of the body
+func (d *Download) Read(p []byte) (int, error) {
+	n, err := d.body.Read(p)
+	if d.hash != nil && n > 0 {
+		d.hash.Write(p[:n])
+	}
+	if err == io.EOF && d.expected != "" {
+		if sum := hex.EncodeToString(d.hash.Sum(nil)); sum != d.expected {
+			return n, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, d.expected, sum)
+		}
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (d *Download) Close() error {
+	return d.body.Close()
+}
+
+// SHA256 returns the hex SHA-256 announced by the server, if any
+func (d *Download) SHA256() string {
+	return parseSHA256(d.Digest)
+}
+
+// resolveURL joins path to the base URL, absolute URLs are kept as is.
+// Requests to another origin are sent without the token and client headers.
+func (c *Client) resolveURL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.BaseURL + path
+}
+
+// digestHeader returns the first digest header sent by the server
+func digestHeader(h http.Header) string {
+	for _, key := range []string{"Repr-Digest", "Digest", "X-Checksum-Sha256"} {
+		if value := h.Get(key); value != "" {
+			if key == "X-Checksum-Sha256" {
+				return "sha-256=" + value
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// parseSHA256 extracts the hex SHA-256 from a digest header, supporting the
+// "sha-256=base64", "sha-256=:base64:" and "sha-256=hex" forms
+func parseSHA256(digest string) string {
+	for _, part := range strings.Split(digest, ",") {
+		algo, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || !strings.EqualFold(algo, "sha-256") {
+			continue
+		}
+		value = strings.Trim(value, ":")
+		if len(value) == sha256.Size*2 {
+			if _, err := hex.DecodeString(value); err == nil {
+				return strings.ToLower(value)
+			}
+		}
+		if raw, err := base64.StdEncoding.DecodeString(value); err == nil && len(raw) == sha256.Size {
+			return hex.EncodeToString(raw)
+		}
+	}
+	return ""
+}
+
+// unsatisfiedRangeSize parses the "bytes */size" Content-Range of a 416
+// response, it returns -1 when the size is missing
+func unsatisfiedRangeSize(value string) int64 {
+	total, ok := strings.CutPrefix(value, "bytes */")
+	if !ok {
+		return -1
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// parseContentRange parses "bytes start-end/size", size being -1 when unknown
+func parseContentRange(value string) (int64, int64, error) {
+	unit, rest, ok := strings.Cut(value, " ")
+	if !ok || unit != "bytes" {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	byteRange, total, ok := strings.Cut(rest, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	startValue, _, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	start, err := strconv.ParseInt(startValue, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	size := int64(-1)
+	if total != "*" {
+		if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+		}
+	}
+	return start, size, nil
+}
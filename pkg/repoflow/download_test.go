@@ -0,0 +1,152 @@
+package repoflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// headerServer records the headers of the last request it received
+func headerServer(t *testing.T, header *http.Header) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*header = r.Header.Clone()
+		io.WriteString(w, "content")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCredentialsStayOnOrigin(t *testing.T) {
+	var apiHeader, otherHeader http.Header
+	api := headerServer(t, &apiHeader)
+	other := headerServer(t, &otherHeader)
+	client := NewClient(api.URL+"/api", WithToken("secret"), WithHeader("X-Api-Key", "key"), WithRetryPolicy(nil))
+
+	tests := []struct {
+		name        string
+		path        string
+		header      *http.Header
+		credentials bool
+	}{
+		{"relative path", "/files/a", &apiHeader, true},
+		{"absolute url on the api", api.URL + "/files/a", &apiHeader, true},
+		{"absolute url elsewhere", other.URL + "/files/a", &otherHeader, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := client.Download(context.Background(), tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d.Close()
+			header := *tt.header
+			if got := header.Get("Authorization") != "" || header.Get("X-Api-Key") != ""; got != tt.credentials {
+				t.Errorf("credentials sent = %v, want %v (%v)", got, tt.credentials, header)
+			}
+		})
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	client := NewClient("https://repoflow.example.com/api")
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://repoflow.example.com/files", true},
+		{"https://REPOFLOW.example.com:443/files", true},
+		{"http://repoflow.example.com/files", false},
+		{"https://repoflow.example.com:8443/files", false},
+		{"https://cdn.example.com/files", false},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if got := client.sameOrigin(u); got != tt.want {
+			t.Errorf("sameOrigin(%s) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+// rangeServer serves content with Range support
+func rangeServer(t *testing.T, content []byte) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDownloadResumeComplete(t *testing.T) {
+	content := []byte("the whole file")
+	sum := sha256.Sum256(content)
+	srv := rangeServer(t, content)
+	client := NewClient(srv.URL, WithRetryPolicy(nil))
+
+	tests := []struct {
+		name    string
+		local   []byte
+		wantErr error
+		status  int
+	}{
+		{"complete", content, nil, 0},
+		{"same size other content", []byte("the whole FILE"), ErrChecksumMismatch, 0},
+		{"larger", append(content, '!'), nil, http.StatusRequestedRangeNotSatisfiable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := sha256.New()
+			h.Write(tt.local)
+			d, err := client.Download(context.Background(), "/file", &DownloadOptions{
+				Offset: int64(len(tt.local)),
+				Hash:   h,
+				SHA256: hex.EncodeToString(sum[:]),
+			})
+			if tt.status != 0 {
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+					t.Errorf("error = %v, want status %d", err, tt.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer d.Close()
+
+			if d.Offset != int64(len(tt.local)) || d.Size != d.Offset || d.ContentLength != 0 {
+				t.Errorf("download = %+v, want an empty complete download", d)
+			}
+			data, err := io.ReadAll(d)
+			if len(data) != 0 || !errors.Is(err, tt.wantErr) {
+				t.Errorf("read = %q, %v, want %v", data, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnsatisfiedRangeSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"bytes */100", 100},
+		{"bytes */*", -1},
+		{"", -1},
+		{"bytes 0-9/100", -1},
+	}
+	for _, tt := range tests {
+		if got := unsatisfiedRangeSize(tt.value); got != tt.want {
+			t.Errorf("unsatisfiedRangeSize(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
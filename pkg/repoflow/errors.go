@@ -0,0 +1,121 @@
+package repoflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// RequestIDHeader is the response header carrying the server request ID
+const RequestIDHeader = "X-Request-Id"
+
+// Sentinel errors matched by *Error through errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// APIError is the single error body returned by some endpoints
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIErrors is the error list body returned by most endpoints
+type APIErrors struct {
+	Errors []string `json:"errors"`
+}
+
+func (e *APIErrors) Error() string {
+	if len(e.Errors) == 0 {
+		return "unknown api error"
+	}
+	return fmt.Sprintf("api error: %v", strings.Join(e.Errors, "; "))
+}
+
+// Error is returned for every API response with a status code >= 400
+type Error struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Code       string
+	Messages   []string
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "api error: %s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Code != "" {
+		fmt.Fprintf(&b, " [%s]", e.Code)
+	}
+	if len(e.Messages) > 0 {
+		fmt.Fprintf(&b, ": %s", strings.Join(e.Messages, "; "))
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// Is matches the sentinel errors according to the HTTP status code
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// CheckResponse returns an *Error when resp has a status code >= 400.
+// The body is consumed but left readable for the caller.
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(RequestIDHeader),
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+
+	if len(body) > 0 {
+		var apiErrs APIErrors
+		if err := json.Unmarshal(body, &apiErrs); err == nil && len(apiErrs.Errors) > 0 {
+			e.Messages = apiErrs.Errors
+			return e
+		}
+
+		var apiErr APIError
+		if err := json.Unmarshal(body, &apiErr); err == nil && (apiErr.Message != "" || apiErr.Code != "") {
+			e.Code = apiErr.Code
+			if apiErr.Message != "" {
+				e.Messages = []string{apiErr.Message}
+			}
+		}
+	}
+
+	return e
+}
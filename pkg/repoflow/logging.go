@@ -0,0 +1,239 @@
+package repoflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Redacted replaces secret values in logs
+const Redacted = "REDACTED"
+
+// maxLoggedBody is the maximum body size written to the logs
+const maxLoggedBody = 64 * 1024
+
+// redactedHeaders lists headers never written to the logs
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactedFields lists JSON fields and query parameters never written to
+// the logs (case insensitive)
+var redactedFields = map[string]bool{
+	"password":                 true,
+	"token":                    true,
+	"access_token":             true,
+	"api_key":                  true,
+	"secret":                   true,
+	"remoterepositorypassword": true,
+}
+
+// LoggingTransport logs every request and response at debug level.
+// Authorization headers and secret body fields are redacted.
+type LoggingTransport struct {
+	Next   http.RoundTripper
+	Logger *slog.Logger
+}
+
+// NewLoggingMiddleware returns a middleware logging the HTTP exchanges with logger
+func NewLoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &LoggingTransport{Next: next, Logger: logger}
+	}
+}
+
+// WithLogging enables the HTTP wire logging with logger.
+// It should be the last middleware so the logs match what is sent.
+func WithLogging(logger *slog.Logger) Option {
+	return WithMiddleware(NewLoggingMiddleware(logger))
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if !logger.Enabled(req.Context(), slog.LevelDebug) {
+		return next.RoundTrip(req)
+	}
+
+	reqBody := ""
+	if req.Body != nil && req.GetBody != nil && !isStreaming(req) && isTextContent(req.Header.Get("Content-Type")) {
+		// Replayable bodies are already in memory, they are redacted whole
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			reqBody = RedactBody(data)
+		}
+	}
+
+	logger.DebugContext(req.Context(), "API Request sent",
+		"method", req.Method,
+		"url", RedactURL(req.URL),
+		"headers", RedactHeaders(req.Header),
+		"payload", reqBody,
+	)
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	latency := time.Since(start)
+	if err != nil {
+		logger.DebugContext(req.Context(), "API Request failed",
+			"method", req.Method,
+			"url", RedactURL(req.URL),
+			"latency", latency,
+			"error", err,
+		)
+		return resp, err
+	}
+
+	respBody := ""
+	switch {
+	case !isTextContent(resp.Header.Get("Content-Type")):
+	case isStreaming(req) || resp.ContentLength < 0 || resp.ContentLength > maxLoggedBody:
+		// Streamed and large bodies are never buffered, a partial document
+		// could not be redacted
+		respBody = bodySize(resp.ContentLength)
+	default:
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		respBody = RedactBody(data)
+	}
+
+	logger.DebugContext(req.Context(), "API Response received",
+		"method", req.Method,
+		"url", RedactURL(req.URL),
+		"status", resp.Status,
+		"latency", latency,
+		"headers", RedactHeaders(resp.Header),
+		"payload", respBody,
+	)
+
+	return resp, nil
+}
+
+// RedactHeaders returns a copy of h with the sensitive headers redacted
+func RedactHeaders(h http.Header) http.Header {
+	redacted := h.Clone()
+	for _, key := range redactedHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, Redacted)
+		}
+	}
+	return redacted
+}
+
+// RedactQuery returns a copy of the query parameters with the secret ones redacted
+func RedactQuery(query url.Values) url.Values {
+	redacted := url.Values{}
+	for key, values := range query {
+		if redactedFields[strings.ToLower(key)] {
+			values = []string{Redacted}
+		}
+		redacted[key] = values
+	}
+	return redacted
+}
+
+// RedactURL returns u as a string with the secret query parameters redacted
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	redacted := *u
+	redacted.RawQuery = RedactQuery(u.Query()).Encode()
+	return redacted.String()
+}
+
+// RedactBody returns the body to log: a JSON document has its secret fields
+// redacted before being truncated to a sane size, other bodies are replaced
+// by their size since they cannot be redacted.
+func RedactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	redacted, ok := redactJSON(body)
+	if !ok {
+		return bodySize(int64(len(body)))
+	}
+	if len(redacted) > maxLoggedBody {
+		return string(redacted[:maxLoggedBody]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+// RedactJSON returns a copy of a JSON document with the secret fields
+// redacted. Other documents are returned unchanged.
+func RedactJSON(body []byte) []byte {
+	if redacted, ok := redactJSON(body); ok {
+		return redacted
+	}
+	return body
+}
+
+// redactJSON redacts a JSON document, ok is false when body is not JSON
+func redactJSON(body []byte) ([]byte, bool) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, false
+	}
+
+	redacted, err := json.Marshal(redactValue(data))
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+// bodySize describes a body which is not logged, size is -1 when unknown
+func bodySize(size int64) string {
+	if size < 0 {
+		return "(streamed body, not logged)"
+	}
+	return fmt.Sprintf("(%d bytes, not logged)", size)
+}
+
+func redactValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if redactedFields[strings.ToLower(key)] {
+				value[key] = Redacted
+				continue
+			}
+			value[key] = redactValue(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+	return v
+}
+
+// isTextContent reports whether the content type can be safely logged
+func isTextContent(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json")
+}
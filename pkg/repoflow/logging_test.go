@@ -0,0 +1,161 @@
+package repoflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	large := []map[string]string{{"name": "npm", "remoteRepositoryPassword": "hunter2"}}
+	for range 5000 {
+		large = append(large, map[string]string{"name": strings.Repeat("x", 20)})
+	}
+	largeBody, _ := json.Marshal(large)
+	if len(largeBody) <= maxLoggedBody {
+		t.Fatalf("large body is %d bytes, want more than %d", len(largeBody), maxLoggedBody)
+	}
+
+	tests := []struct {
+		name      string
+		body      []byte
+		contains  string
+		forbidden string
+	}{
+		{"empty", nil, "", ""},
+		{"secret field", []byte(`{"name":"npm","password":"hunter2"}`), Redacted, "hunter2"},
+		{"nested secret", []byte(`{"repo":{"remoteRepositoryPassword":"hunter2"}}`), Redacted, "hunter2"},
+		{"case insensitive", []byte(`[{"Token":"hunter2"}]`), Redacted, "hunter2"},
+		{"large document", largeBody, "(truncated)", "hunter2"},
+		{"not json", []byte("password=hunter2"), "(16 bytes, not logged)", "hunter2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RedactBody(tt.body)
+			if !strings.Contains(got, tt.contains) {
+				t.Errorf("RedactBody() = %.100q, want it to contain %q", got, tt.contains)
+			}
+			if tt.forbidden != "" && strings.Contains(got, tt.forbidden) {
+				t.Errorf("RedactBody() leaks %q", tt.forbidden)
+			}
+			if len(got) > maxLoggedBody+len("...(truncated)") {
+				t.Errorf("RedactBody() is %d bytes long", len(got))
+			}
+		})
+	}
+}
+
+// readCounter counts the bytes read from a response body
+type readCounter struct {
+	io.ReadCloser
+	n int
+}
+
+func (r *readCounter) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += n
+	return n, err
+}
+
+// bodyCounter replaces response bodies with counting readers
+type bodyCounter struct {
+	next http.RoundTripper
+	body *readCounter
+}
+
+func (b *bodyCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := b.next.RoundTrip(req)
+	if err == nil {
+		b.body = &readCounter{ReadCloser: resp.Body}
+		resp.Body = b.body
+	}
+	return resp, err
+}
+
+func TestLoggingTransport(t *testing.T) {
+	payload := `{"name":"npm","token":"hunter2"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/chunked" {
+			// Flushing before the end forces a chunked response
+			io.WriteString(w, payload[:10])
+			w.(http.Flusher).Flush()
+		} else {
+			w.Header().Set("Content-Length", "32")
+			io.WriteString(w, payload[:10])
+		}
+		io.WriteString(w, payload[10:])
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		streaming bool
+		buffered  bool
+	}{
+		{"known length", "/sized", false, true},
+		{"chunked", "/chunked", false, false},
+		{"streaming request", "/sized", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			counter := &bodyCounter{next: http.DefaultTransport}
+			transport := &LoggingTransport{Next: counter, Logger: logger}
+
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+tt.path, nil)
+			if tt.streaming {
+				req = withStreaming(req)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if buffered := counter.body.n > 0; buffered != tt.buffered {
+				t.Errorf("body read before the caller: %v, want %v", buffered, tt.buffered)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != payload {
+				t.Errorf("body = %q, want %q", body, payload)
+			}
+			if strings.Contains(logs.String(), "hunter2") {
+				t.Errorf("logs leak the token: %s", logs.String())
+			}
+			if tt.buffered && !strings.Contains(logs.String(), Redacted) {
+				t.Errorf("logs miss the redacted payload: %s", logs.String())
+			}
+		})
+	}
+}
+
+func TestLoggingTransportRedactsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(srv.URL, WithToken("secret-token"), WithLogging(logger))
+
+	err := client.DoRequest(context.Background(), http.MethodPost, "/1/test",
+		map[string]string{"remoteRepositoryPassword": "hunter2", "padding": strings.Repeat("x", maxLoggedBody)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "secret-token"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("logs leak %q", secret)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package repoflow
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultUserAgent is sent when no user agent is configured
+const DefaultUserAgent = "go-repoflow"
+
+// Option configures a Client built by NewClient
+type Option func(*Client)
+
+// Middleware wraps the HTTP transport used by the client.
+// It can be used to inject authentication, tracing, metrics or logging.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// WithHTTPClient sets the base HTTP client.
+// The client is copied, so middlewares and timeout never alter the original.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithToken sets the personal token sent as a bearer token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.Token = token
+	}
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithTimeout sets the overall timeout of a single HTTP attempt
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = &timeout
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.Header.Add(key, value)
+	}
+}
+
+// WithMiddleware appends transport middlewares.
+// The first registered middleware is the outermost one.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, mw...)
+	}
+}
+
+// WithOuterMiddleware appends transport middlewares wrapping the cache and
+// the client limits, so they see every request made by the client, cache
+// hits included. The first registered middleware is the outermost one.
+func WithOuterMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.outerMiddlewares = append(c.outerMiddlewares, mw...)
+	}
+}
+
+// WithRetryPolicy sets the retry policy, nil disables retries
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithLogger sets the logger used by the client
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.Logger = logger
+	}
+}
+
+// buildHTTPClient copies the configured HTTP client and applies the
+// timeout, the middleware chain, the client limits and the cache
+func (c *Client) buildHTTPClient() {
+	hc := http.Client{Timeout: DefaultTimeout}
+	if c.HTTPClient != nil {
+		hc = *c.HTTPClient
+	}
+	if c.timeout != nil {
+		hc.Timeout = *c.timeout
+	}
+	if c.tlsConfig != nil || c.proxy != nil {
+		hc.Transport = c.baseTransport(hc.Transport)
+	}
+
+	if len(c.middlewares) > 0 || len(c.outerMiddlewares) > 0 || c.limiter != nil || c.slots != nil || c.cache != nil {
+		transport := hc.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		for i := len(c.middlewares) - 1; i >= 0; i-- {
+			transport = c.middlewares[i](transport)
+		}
+		// Limits are the outermost layer so waiting never counts in latency
+		if c.limiter != nil || c.slots != nil {
+			transport = &limitTransport{next: transport, limiter: c.limiter, slots: c.slots}
+		}
+		if c.cache != nil {
+			transport = c.cache.Middleware()(transport)
+		}
+		for i := len(c.outerMiddlewares) - 1; i >= 0; i-- {
+			transport = c.outerMiddlewares[i](transport)
+		}
+		hc.Transport = transport
+	}
+
+	c.HTTPClient = &hc
+}
+
+// baseTransport returns a copy of transport with the client TLS and proxy settings.
+// Only *http.Transport can be configured, other transports are kept as is.
+func (c *Client) baseTransport(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		c.logger().Warn("Custom HTTP transport, TLS and proxy settings are ignored")
+		return transport
+	}
+
+	base = base.Clone()
+	if c.tlsConfig != nil {
+		base.TLSClientConfig = c.tlsConfig
+		if c.tlsConfig.InsecureSkipVerify {
+			c.logger().Warn("TLS certificate verification is DISABLED, connections are vulnerable to interception",
+				"url", c.BaseURL,
+			)
+		}
+	}
+	if c.proxy != nil {
+		base.Proxy = c.proxy
+	}
+	return base
+}
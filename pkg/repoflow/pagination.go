@@ -0,0 +1,34 @@
+package repoflow
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the page size used by the iterators
+const DefaultPageSize = 100
+
+// ListOptions defines the paging of list endpoints
+type ListOptions struct {
+	Offset int
+	Limit  int
+}
+
+// query returns the query string for the paging options
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+
+	values := url.Values{}
+	if o.Offset > 0 {
+		values.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
@@ -0,0 +1,112 @@
+package repoflow
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyOptions defines the HTTP proxy used to reach the API
+type ProxyOptions struct {
+	// URL of the proxy, the HTTP(S)_PROXY environment variables are used when empty
+	URL string
+	// Username and Password authenticate against the proxy
+	Username string
+	Password string
+	// NoProxy lists hosts reached directly: host names, domain suffixes
+	// (".example.com"), IP addresses, CIDR ranges or "*"
+	NoProxy []string
+}
+
+// environmentProxy selects the proxy from the HTTP(S)_PROXY variables
+var environmentProxy = http.ProxyFromEnvironment
+
+// Func returns the proxy selection function for http.Transport.Proxy.
+// NoProxy applies to the environment proxy as well, credentials require URL.
+func (o ProxyOptions) Func() (func(*http.Request) (*url.URL, error), error) {
+	noProxy := o.NoProxy
+	if o.URL == "" {
+		if o.Username != "" || o.Password != "" {
+			return nil, fmt.Errorf("proxy credentials require a proxy url")
+		}
+		return func(req *http.Request) (*url.URL, error) {
+			if bypassProxy(req.URL, noProxy) {
+				return nil, nil
+			}
+			return environmentProxy(req)
+		}, nil
+	}
+
+	proxyURL, err := url.Parse(o.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url: %s", o.URL)
+	}
+	if o.Username != "" {
+		proxyURL.User = url.UserPassword(o.Username, o.Password)
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL, noProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// bypassProxy reports whether u matches one of the no proxy entries
+func bypassProxy(u *url.URL, noProxy []string) bool {
+	host := u.Hostname()
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case strings.Contains(entry, "/"):
+			if _, network, err := net.ParseCIDR(entry); err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+		default:
+			if h, port, err := net.SplitHostPort(entry); err == nil {
+				if port != urlPort(u) {
+					continue
+				}
+				entry = h
+			}
+			domain := strings.TrimPrefix(entry, ".")
+			if strings.EqualFold(host, domain) || strings.HasSuffix(strings.ToLower(host), "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// urlPort returns the port of u, the default one of its scheme when implicit
+func urlPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch u.Scheme {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	}
+	return ""
+}
+
+// WithProxy sets the proxy selection function of the HTTP transport
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(c *Client) {
+		c.proxy = proxy
+	}
+}
@@ -0,0 +1,151 @@
+package repoflow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// canceledContext is done from the start, so a limiter that would wait
+// fails at once instead of sleeping
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+// allowed counts the requests allowed by l without waiting, up to max
+func allowed(l *RateLimiter, max int) int {
+	ctx := canceledContext()
+	for n := range max {
+		if err := l.Wait(ctx); err != nil {
+			return n
+		}
+	}
+	return max
+}
+
+// elapse moves the last refill of l back by d, as if d had passed
+func elapse(l *RateLimiter, d time.Duration) {
+	l.mu.Lock()
+	l.last = l.last.Add(-d)
+	l.mu.Unlock()
+}
+
+func TestRateLimiterBucket(t *testing.T) {
+	l := NewRateLimiter(1, 3)
+	if n := allowed(l, 10); n != 3 {
+		t.Fatalf("burst = %d, want 3", n)
+	}
+
+	elapse(l, 2*time.Second)
+	if n := allowed(l, 10); n != 2 {
+		t.Errorf("allowed after 2s = %d, want 2", n)
+	}
+
+	// The bucket never holds more than the burst
+	elapse(l, time.Hour)
+	if n := allowed(l, 10); n != 3 {
+		t.Errorf("allowed after 1h = %d, want the burst of 3", n)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if n := allowed(l, 1); n != 1 {
+		t.Fatalf("burst = %d, want 1", n)
+	}
+
+	// Canceled waits give their token back, so they do not delay the others
+	for range 5 {
+		if err := l.Wait(canceledContext()); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Wait() error = %v, want context.Canceled", err)
+		}
+	}
+	elapse(l, time.Second)
+	if n := allowed(l, 10); n != 1 {
+		t.Errorf("allowed after 1s = %d, want 1", n)
+	}
+}
+
+func TestRateLimiterWaits(t *testing.T) {
+	l := NewRateLimiter(1000, 1)
+	allowed(l, 1)
+
+	start := time.Now()
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waited %s, want about 1ms", elapsed)
+	}
+}
+
+func TestRateLimiterInvalidRate(t *testing.T) {
+	for _, rps := range []float64{0, -1, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		l := NewRateLimiter(rps, 0)
+		if n := allowed(l, 100); n != 100 {
+			t.Errorf("NewRateLimiter(%v) allowed %d requests, want no limit", rps, n)
+		}
+	}
+}
+
+// stubTransport answers every request with an empty body
+type stubTransport struct {
+	requests int
+}
+
+func (t *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestLimitTransportSlots(t *testing.T) {
+	next := &stubTransport{}
+	transport := &limitTransport{next: next, slots: make(chan struct{}, 1)}
+	req := func(ctx context.Context) *http.Request {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://repoflow.test/", nil)
+		return req
+	}
+
+	first, err := transport.RoundTrip(req(context.Background()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The slot is held until the body is closed
+	if _, err := transport.RoundTrip(req(canceledContext())); !errors.Is(err, context.Canceled) {
+		t.Errorf("second request error = %v, want context.Canceled", err)
+	}
+	first.Body.Close()
+	first.Body.Close()
+
+	second, err := transport.RoundTrip(req(context.Background()))
+	if err != nil {
+		t.Fatalf("request after release: %v", err)
+	}
+	second.Body.Close()
+	if next.requests != 2 {
+		t.Errorf("requests sent = %d, want 2", next.requests)
+	}
+}
+
+func TestLimitTransportReleasesOnLimiterCancel(t *testing.T) {
+	next := &stubTransport{}
+	limiter := NewRateLimiter(1, 1)
+	allowed(limiter, 1)
+	transport := &limitTransport{next: next, limiter: limiter, slots: make(chan struct{}, 1)}
+
+	req, _ := http.NewRequestWithContext(canceledContext(), http.MethodGet, "http://repoflow.test/", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if len(transport.slots) != 0 || next.requests != 0 {
+		t.Errorf("slots held = %d, requests sent = %d, want 0 and 0", len(transport.slots), next.requests)
+	}
+}
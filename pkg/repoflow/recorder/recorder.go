@@ -0,0 +1,331 @@
+// Package recorder provides a record/replay http.RoundTripper to test code
+// built on the repoflow client without a live server.
+//
+// Interactions are stored in JSON cassette files. Authorization headers,
+// secret query parameters and secret body fields are scrubbed before being
+// written, and requests are matched on method, path, query and body,
+// ignoring the server host.
+//
+//	rec, err := recorder.New("testdata/workspaces.json", recorder.ModeAuto)
+//	client := repoflow.NewClient(url,
+//		rec.Option(),
+//		repoflow.WithRetryPolicy(nil),
+//	)
+//	defer rec.Save()
+//
+// The recorder wraps the cache and the client limits, so every request made
+// by the client is recorded. Disabling retries makes unknown requests fail
+// fast in replay mode.
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// Mode defines how the recorder handles requests
+type Mode int
+
+const (
+	// ModeReplay serves requests from the cassette and fails on unknown requests
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the server and records the interactions
+	ModeRecord
+	// ModeAuto replays the cassette when it exists and records it otherwise
+	ModeAuto
+)
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction matches
+var ErrNoInteraction = errors.New("recorder: no matching interaction")
+
+// Cassette holds the recorded interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of an HTTP request
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+// Response is the recorded part of an HTTP response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Body is a payload stored as text, or as base64 when it is binary
+type Body struct {
+	Data     string `json:"data,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Recorder is a record/replay http.RoundTripper
+type Recorder struct {
+	// Next is the transport used in record mode
+	Next http.RoundTripper
+	// Scrub is called on every interaction before it is recorded,
+	// to remove secrets not handled by default
+	Scrub func(*Interaction)
+
+	path     string
+	mode     Mode
+	mu       sync.Mutex
+	cassette Cassette
+	used     map[*Interaction]bool
+}
+
+// New creates a recorder backed by the cassette file at path
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, used: map[*Interaction]bool{}}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if mode == ModeAuto {
+			r.mode = ModeReplay
+		}
+		if r.mode == ModeReplay {
+			if err := json.Unmarshal(data, &r.cassette); err != nil {
+				return nil, fmt.Errorf("recorder: invalid cassette %s: %w", path, err)
+			}
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		if mode == ModeReplay {
+			return nil, fmt.Errorf("recorder: cassette %s not found", path)
+		}
+		r.mode = ModeRecord
+	default:
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Mode returns the effective mode of the recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Middleware returns the recorder as a client middleware, the wrapped
+// transport being used in record mode
+func (r *Recorder) Middleware() repoflow.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		r.Next = next
+		return r
+	}
+}
+
+// Option plugs the recorder in a client, around the cache and the limits
+func (r *Recorder) Option() repoflow.Option {
+	return repoflow.WithOuterMiddleware(r.Middleware())
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  repoflow.RedactQuery(req.URL.Query()).Encode(),
+		Header: repoflow.RedactHeaders(req.Header),
+		Body:   newBody(repoflow.RedactJSON(body)),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// First unused interaction wins, then any matching one
+	var match *Interaction
+	for _, i := range r.cassette.Interactions {
+		if i.Request.matches(recorded) {
+			if !r.used[i] {
+				match = i
+				break
+			}
+			if match == nil {
+				match = i
+			}
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+	}
+	r.used[match] = true
+
+	body, err := match.Response.Body.bytes()
+	if err != nil {
+		return nil, err
+	}
+	// Scrubbing may have changed the body length
+	header := match.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Del("Content-Length")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		StatusCode:    match.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	next := r.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := &Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     repoflow.RedactHeaders(resp.Header),
+			Body:       newBody(repoflow.RedactJSON(body)),
+		},
+	}
+	if r.Scrub != nil {
+		r.Scrub(interaction)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette file.
+// It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// matches compares the method, path, query and body of two requests
+func (req Request) matches(other Request) bool {
+	return req.Method == other.Method &&
+		req.Path == other.Path &&
+		req.Query == other.Query &&
+		equalBody(req.Body, other.Body)
+}
+
+// equalBody compares bodies, JSON documents being compared semantically
+func equalBody(a, b Body) bool {
+	if a == b {
+		return true
+	}
+	if a.Encoding != "" || b.Encoding != "" {
+		return false
+	}
+
+	var va, vb any
+	if json.Unmarshal([]byte(a.Data), &va) != nil || json.Unmarshal([]byte(b.Data), &vb) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
+
+func newBody(data []byte) Body {
+	if utf8.Valid(data) {
+		return Body{Data: string(data)}
+	}
+	return Body{Data: base64.StdEncoding.EncodeToString(data), Encoding: "base64"}
+}
+
+func (b Body) bytes() ([]byte, error) {
+	if strings.EqualFold(b.Encoding, "base64") {
+		return base64.StdEncoding.DecodeString(b.Data)
+	}
+	return []byte(b.Data), nil
+}
+
+// readRequestBody reads the request body and leaves it readable
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
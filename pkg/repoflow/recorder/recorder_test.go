@@ -0,0 +1,225 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// newServer returns a server answering the workspace list and echoing the
+// POST bodies, hits counts the requests it received
+func newServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			io.WriteString(w, `[{"id":"ws-1","name":"main"}]`)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"id":"ws-2","name":"new","token":"server-secret"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	var hits atomic.Int32
+	srv := newServer(t, &hits)
+	cassette := filepath.Join(t.TempDir(), "cassettes", "workspaces.json")
+	ctx := context.Background()
+
+	rec, err := New(cassette, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("mode = %v, want record without cassette", rec.Mode())
+	}
+	client := repoflow.NewClient(srv.URL, rec.Option(), repoflow.WithToken("client-secret"), repoflow.WithRetryPolicy(nil))
+	if _, err := client.ListWorkspaces(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DoRequest(ctx, http.MethodGet, "/1/workspaces?token=query-secret&limit=1", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateWorkspace(ctx, repoflow.WorkspaceOptions{Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"client-secret", "query-secret", "server-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette leaks %q", secret)
+		}
+	}
+
+	// Replay against a closed server, only the cassette can answer
+	srv.Close()
+	recorded := hits.Load()
+	replay, err := New(cassette, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Mode() != ModeReplay {
+		t.Fatalf("mode = %v, want replay with a cassette", replay.Mode())
+	}
+	client = repoflow.NewClient(srv.URL, replay.Option(), repoflow.WithToken("other-token"), repoflow.WithRetryPolicy(nil))
+
+	workspaces, err := client.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*workspaces) != 1 || (*workspaces)[0].Name != "main" {
+		t.Errorf("replayed workspaces = %+v", *workspaces)
+	}
+	// The scrubbed query still matches
+	if err := client.DoRequest(ctx, http.MethodGet, "/1/workspaces?limit=1&token=other-secret", nil, nil); err != nil {
+		t.Errorf("query request not replayed: %v", err)
+	}
+	ws, err := client.CreateWorkspace(ctx, repoflow.WorkspaceOptions{Name: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Id != "ws-2" {
+		t.Errorf("replayed workspace = %+v", ws)
+	}
+	if hits.Load() != recorded {
+		t.Errorf("replay reached the server")
+	}
+
+	_, err = client.CreateWorkspace(ctx, repoflow.WorkspaceOptions{Name: "other"})
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("unknown request error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestReplayCassette(t *testing.T) {
+	rec, err := New("testdata/workspaces.json", ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := repoflow.NewClient("http://repoflow.invalid", rec.Option(), repoflow.WithRetryPolicy(nil))
+	ctx := context.Background()
+
+	workspaces, err := client.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*workspaces) != 2 {
+		t.Errorf("workspaces = %+v", *workspaces)
+	}
+
+	// JSON bodies match regardless of the key order
+	limit := 10
+	ws, err := client.CreateWorkspace(ctx, repoflow.WorkspaceOptions{PackageLimit: &limit, Name: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Id != "ws-3" {
+		t.Errorf("workspace = %+v", ws)
+	}
+
+	// Interactions can be replayed more than once
+	if _, err := client.ListWorkspaces(ctx); err != nil {
+		t.Errorf("second replay: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Errorf("Save in replay mode: %v", err)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("New succeeded without cassette")
+	}
+}
+
+func TestRecorderWrapsCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := newServer(t, &hits)
+	cassette := filepath.Join(t.TempDir(), "cached.json")
+
+	rec, err := New(cassette, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := repoflow.NewCache(t.TempDir(), time.Hour)
+	client := repoflow.NewClient(srv.URL, rec.Option(), repoflow.WithCache(cache), repoflow.WithRetryPolicy(nil))
+	for range 2 {
+		if _, err := client.ListWorkspaces(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rec.Save()
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits = %d, want 1 with the cache", got)
+	}
+	replay, err := New(cassette, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(replay.cassette.Interactions); got != 2 {
+		t.Errorf("recorded interactions = %d, want 2 including the cache hit", got)
+	}
+}
+
+func TestRecorderScrub(t *testing.T) {
+	var hits atomic.Int32
+	srv := newServer(t, &hits)
+	cassette := filepath.Join(t.TempDir(), "scrubbed.json")
+
+	rec, err := New(cassette, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Scrub = func(i *Interaction) {
+		i.Response.Body.Data = strings.ReplaceAll(i.Response.Body.Data, "main", "scrubbed")
+	}
+	client := repoflow.NewClient(srv.URL, rec.Option(), repoflow.WithRetryPolicy(nil))
+	if _, err := client.ListWorkspaces(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rec.Save()
+
+	data, _ := os.ReadFile(cassette)
+	if strings.Contains(string(data), "main") || !strings.Contains(string(data), "scrubbed") {
+		t.Errorf("Scrub not applied: %s", data)
+	}
+}
+
+func TestBody(t *testing.T) {
+	binary := []byte{0xff, 0x00, 0xfe}
+	body := newBody(binary)
+	if body.Encoding != "base64" {
+		t.Errorf("binary body encoding = %q, want base64", body.Encoding)
+	}
+	if got, err := body.bytes(); err != nil || string(got) != string(binary) {
+		t.Errorf("binary round trip = %v, %v", got, err)
+	}
+
+	if !equalBody(Body{Data: `{"a":1,"b":2}`}, Body{Data: `{"b":2, "a":1}`}) {
+		t.Error("equivalent JSON bodies do not match")
+	}
+	if equalBody(Body{Data: `{"a":1}`}, Body{Data: `{"a":2}`}) {
+		t.Error("different JSON bodies match")
+	}
+}
@@ -0,0 +1,242 @@
+package repoflowtest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// ErrNotImplemented is returned by the fakes when the function field of a
+// method is not set
+var ErrNotImplemented = errors.New("repoflowtest: method not implemented")
+
+// FakeWorkspaceService implements repoflow.WorkspaceService with function fields
+type FakeWorkspaceService struct {
+	ListWorkspacesFunc  func(ctx context.Context) (*[]repoflow.Workspaces, error)
+	CreateWorkspaceFunc func(ctx context.Context, opts repoflow.WorkspaceOptions) (*repoflow.Workspace, error)
+	GetWorkspaceFunc    func(ctx context.Context, id string) (*repoflow.Workspace, error)
+	DeleteWorkspaceFunc func(ctx context.Context, id string) (*repoflow.Workspace, error)
+}
+
+var _ repoflow.WorkspaceService = (*FakeWorkspaceService)(nil)
+
+func (f *FakeWorkspaceService) ListWorkspaces(ctx context.Context) (*[]repoflow.Workspaces, error) {
+	if f.ListWorkspacesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.ListWorkspacesFunc(ctx)
+}
+
+func (f *FakeWorkspaceService) CreateWorkspace(ctx context.Context, opts repoflow.WorkspaceOptions) (*repoflow.Workspace, error) {
+	if f.CreateWorkspaceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.CreateWorkspaceFunc(ctx, opts)
+}
+
+func (f *FakeWorkspaceService) GetWorkspace(ctx context.Context, id string) (*repoflow.Workspace, error) {
+	if f.GetWorkspaceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.GetWorkspaceFunc(ctx, id)
+}
+
+func (f *FakeWorkspaceService) DeleteWorkspace(ctx context.Context, id string) (*repoflow.Workspace, error) {
+	if f.DeleteWorkspaceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.DeleteWorkspaceFunc(ctx, id)
+}
+
+// FakeRepositoryService implements repoflow.RepositoryService with function fields
+type FakeRepositoryService struct {
+	ListRepositoriesFunc        func(ctx context.Context, workspace string) (*[]repoflow.Repositories, error)
+	GetRepositoryFunc           func(ctx context.Context, workspace string, id string) (*repoflow.Repository, error)
+	ListRepositoryPackagesFunc  func(ctx context.Context, workspace string, id string, opts *repoflow.ListOptions) (*repoflow.RepositoryPackages, error)
+	AllRepositoryPackagesFunc   func(ctx context.Context, workspace string, id string, opts *repoflow.ListOptions) iter.Seq2[*repoflow.PackageRepository, error]
+	CreateRepositoryFunc        func(ctx context.Context, workspace string, store string, opts any) (*repoflow.Repository, error)
+	CreateLocalRepositoryFunc   func(ctx context.Context, workspace string, opts repoflow.RepositoryOptions) (*repoflow.Repository, error)
+	CreateRemoteRepositoryFunc  func(ctx context.Context, workspace string, opts repoflow.RepositoryRemoteOptions) (*repoflow.Repository, error)
+	CreateVirtualRepositoryFunc func(ctx context.Context, workspace string, opts repoflow.RepositoryVirtualOptions) (*repoflow.Repository, error)
+	UpdateRepositoryFunc        func(ctx context.Context, workspace string, store string, id string, opts any) (*repoflow.Repository, error)
+	UpdateLocalRepositoryFunc   func(ctx context.Context, workspace string, id string, opts repoflow.RepositoryUpdateOptions) (*repoflow.Repository, error)
+	UpdateRemoteRepositoryFunc  func(ctx context.Context, workspace string, id string, opts repoflow.RepositoryRemoteUpdateOptions) (*repoflow.Repository, error)
+	UpdateVirtualRepositoryFunc func(ctx context.Context, workspace string, id string, opts repoflow.RepositoryVirtualUpdateOptions) (*repoflow.Repository, error)
+	DeleteRepositoryFunc        func(ctx context.Context, workspace string, id string) (*repoflow.RepostotryDelete, error)
+	DeleteRepositoryContentFunc func(ctx context.Context, workspace string, id string) (*repoflow.RepostotryDelete, error)
+}
+
+var _ repoflow.RepositoryService = (*FakeRepositoryService)(nil)
+
+func (f *FakeRepositoryService) ListRepositories(ctx context.Context, workspace string) (*[]repoflow.Repositories, error) {
+	if f.ListRepositoriesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.ListRepositoriesFunc(ctx, workspace)
+}
+
+func (f *FakeRepositoryService) GetRepository(ctx context.Context, workspace string, id string) (*repoflow.Repository, error) {
+	if f.GetRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.GetRepositoryFunc(ctx, workspace, id)
+}
+
+func (f *FakeRepositoryService) ListRepositoryPackages(ctx context.Context, workspace string, id string, opts *repoflow.ListOptions) (*repoflow.RepositoryPackages, error) {
+	if f.ListRepositoryPackagesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.ListRepositoryPackagesFunc(ctx, workspace, id, opts)
+}
+
+func (f *FakeRepositoryService) AllRepositoryPackages(ctx context.Context, workspace string, id string, opts *repoflow.ListOptions) iter.Seq2[*repoflow.PackageRepository, error] {
+	if f.AllRepositoryPackagesFunc == nil {
+		return func(yield func(*repoflow.PackageRepository, error) bool) {
+			yield(nil, ErrNotImplemented)
+		}
+	}
+	return f.AllRepositoryPackagesFunc(ctx, workspace, id, opts)
+}
+
+func (f *FakeRepositoryService) CreateRepository(ctx context.Context, workspace string, store string, opts any) (*repoflow.Repository, error) {
+	if f.CreateRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.CreateRepositoryFunc(ctx, workspace, store, opts)
+}
+
+func (f *FakeRepositoryService) CreateLocalRepository(ctx context.Context, workspace string, opts repoflow.RepositoryOptions) (*repoflow.Repository, error) {
+	if f.CreateLocalRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.CreateLocalRepositoryFunc(ctx, workspace, opts)
+}
+
+func (f *FakeRepositoryService) CreateRemoteRepository(ctx context.Context, workspace string, opts repoflow.RepositoryRemoteOptions) (*repoflow.Repository, error) {
+	if f.CreateRemoteRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.CreateRemoteRepositoryFunc(ctx, workspace, opts)
+}
+
+func (f *FakeRepositoryService) CreateVirtualRepository(ctx context.Context, workspace string, opts repoflow.RepositoryVirtualOptions) (*repoflow.Repository, error) {
+	if f.CreateVirtualRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.CreateVirtualRepositoryFunc(ctx, workspace, opts)
+}
+
+func (f *FakeRepositoryService) UpdateRepository(ctx context.Context, workspace string, store string, id string, opts any) (*repoflow.Repository, error) {
+	if f.UpdateRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.UpdateRepositoryFunc(ctx, workspace, store, id, opts)
+}
+
+func (f *FakeRepositoryService) UpdateLocalRepository(ctx context.Context, workspace string, id string, opts repoflow.RepositoryUpdateOptions) (*repoflow.Repository, error) {
+	if f.UpdateLocalRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.UpdateLocalRepositoryFunc(ctx, workspace, id, opts)
+}
+
+func (f *FakeRepositoryService) UpdateRemoteRepository(ctx context.Context, workspace string, id string, opts repoflow.RepositoryRemoteUpdateOptions) (*repoflow.Repository, error) {
+	if f.UpdateRemoteRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.UpdateRemoteRepositoryFunc(ctx, workspace, id, opts)
+}
+
+func (f *FakeRepositoryService) UpdateVirtualRepository(ctx context.Context, workspace string, id string, opts repoflow.RepositoryVirtualUpdateOptions) (*repoflow.Repository, error) {
+	if f.UpdateVirtualRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.UpdateVirtualRepositoryFunc(ctx, workspace, id, opts)
+}
+
+func (f *FakeRepositoryService) DeleteRepository(ctx context.Context, workspace string, id string) (*repoflow.RepostotryDelete, error) {
+	if f.DeleteRepositoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.DeleteRepositoryFunc(ctx, workspace, id)
+}
+
+func (f *FakeRepositoryService) DeleteRepositoryContent(ctx context.Context, workspace string, id string) (*repoflow.RepostotryDelete, error) {
+	if f.DeleteRepositoryContentFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.DeleteRepositoryContentFunc(ctx, workspace, id)
+}
+
+// FakeStatusService implements repoflow.StatusService with function fields
+type FakeStatusService struct {
+	PingFunc       func(ctx context.Context) (time.Duration, error)
+	ServerInfoFunc func(ctx context.Context) (*repoflow.ServerInfo, error)
+}
+
+var _ repoflow.StatusService = (*FakeStatusService)(nil)
+
+func (f *FakeStatusService) Ping(ctx context.Context) (time.Duration, error) {
+	if f.PingFunc == nil {
+		return 0, ErrNotImplemented
+	}
+	return f.PingFunc(ctx)
+}
+
+func (f *FakeStatusService) ServerInfo(ctx context.Context) (*repoflow.ServerInfo, error) {
+	if f.ServerInfoFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.ServerInfoFunc(ctx)
+}
+
+// FakeRequestService implements repoflow.RequestService with function fields.
+// DoRequest falls back to DoRequestWithResponseFunc when DoRequestFunc is not set.
+type FakeRequestService struct {
+	DoRequestFunc             func(ctx context.Context, method, path string, body interface{}, result interface{}) error
+	DoRequestWithResponseFunc func(ctx context.Context, method, path string, body interface{}, result interface{}) (*repoflow.Response, error)
+}
+
+var _ repoflow.RequestService = (*FakeRequestService)(nil)
+
+func (f *FakeRequestService) DoRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	if f.DoRequestFunc == nil {
+		_, err := f.DoRequestWithResponse(ctx, method, path, body, result)
+		return err
+	}
+	return f.DoRequestFunc(ctx, method, path, body, result)
+}
+
+func (f *FakeRequestService) DoRequestWithResponse(ctx context.Context, method, path string, body interface{}, result interface{}) (*repoflow.Response, error) {
+	if f.DoRequestWithResponseFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.DoRequestWithResponseFunc(ctx, method, path, body, result)
+}
+
+// FakeTransferService implements repoflow.TransferService with function
+// fields, downloads can be built with repoflow.NewDownload
+type FakeTransferService struct {
+	DownloadFunc func(ctx context.Context, path string, opts *repoflow.DownloadOptions) (*repoflow.Download, error)
+	UploadFunc   func(ctx context.Context, path string, content io.Reader, opts *repoflow.UploadOptions) (*repoflow.UploadResult, error)
+}
+
+var _ repoflow.TransferService = (*FakeTransferService)(nil)
+
+func (f *FakeTransferService) Download(ctx context.Context, path string, opts *repoflow.DownloadOptions) (*repoflow.Download, error) {
+	if f.DownloadFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.DownloadFunc(ctx, path, opts)
+}
+
+func (f *FakeTransferService) Upload(ctx context.Context, path string, content io.Reader, opts *repoflow.UploadOptions) (*repoflow.UploadResult, error) {
+	if f.UploadFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.UploadFunc(ctx, path, content, opts)
+}
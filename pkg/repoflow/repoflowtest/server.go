@@ -0,0 +1,729 @@
+// Package repoflowtest provides an in-memory RepoFlow server to test code
+// built on the repoflow client end to end.
+//
+//	srv := repoflowtest.NewServer()
+//	defer srv.Close()
+//
+//	ws := srv.AddWorkspace("team")
+//	client := srv.Client()
+//	repos, err := client.ListRepositories(ctx, ws.Id)
+package repoflowtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+)
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Failure is an error injected by InjectFailure
+type Failure struct {
+	// Method matches the request method, any method when empty
+	Method string
+	// Path is a path.Match pattern on the request path, any path when empty
+	Path string
+	// Status is the HTTP status code returned
+	Status int
+	// Messages are returned in the APIErrors body
+	Messages []string
+	// Header is added to the response, for instance Retry-After
+	Header http.Header
+	// Times is the number of requests failing, 0 for every request
+	Times int
+}
+
+// Server is an in-memory RepoFlow API
+type Server struct {
+	*httptest.Server
+
+	// Token is the bearer token required by the server, any token is
+	// accepted when empty
+	Token string
+	// Version is served by the info endpoint, which answers 404 when empty
+	Version string
+	// Identity is served as the current user, which answers 404 when nil
+	Identity *repoflow.Identity
+
+	mu           sync.Mutex
+	nextID       int
+	latency      time.Duration
+	failures     []*Failure
+	requests     []Request
+	workspaces   []*repoflow.Workspace
+	repositories map[string][]*repoflow.Repository
+	packages     map[string][]*repoflow.PackageRepository
+}
+
+// NewServer starts a new server, it must be closed by the caller
+func NewServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// NewTLSServer starts a new server using TLS, it must be closed by the caller
+func NewTLSServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(s.handler())
+	return s
+}
+
+func newServer() *Server {
+	return &Server{
+		repositories: map[string][]*repoflow.Repository{},
+		packages:     map[string][]*repoflow.PackageRepository{},
+	}
+}
+
+// Client returns a client configured for the server.
+// Retries are disabled so injected failures are returned immediately.
+func (s *Server) Client(opts ...repoflow.Option) *repoflow.Client {
+	options := []repoflow.Option{
+		repoflow.WithHTTPClient(s.Server.Client()),
+		repoflow.WithToken(s.Token),
+		repoflow.WithRetryPolicy(nil),
+	}
+	return repoflow.NewClient(s.URL, append(options, opts...)...)
+}
+
+// AddWorkspace seeds a workspace
+func (s *Server) AddWorkspace(name string) *repoflow.Workspace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := &repoflow.Workspace{Id: s.newID("ws"), Name: name}
+	s.workspaces = append(s.workspaces, ws)
+	return ws
+}
+
+// AddRepository seeds a repository in a workspace.
+// The ID is generated when empty.
+func (s *Server) AddRepository(workspaceID string, repo repoflow.Repository) *repoflow.Repository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if repo.Id == "" {
+		repo.Id = s.newID("repo")
+	}
+	if repo.Status == "" {
+		repo.Status = "active"
+	}
+	repo.WorkspaceId = workspaceID
+	s.repositories[workspaceID] = append(s.repositories[workspaceID], &repo)
+	return &repo
+}
+
+// AddPackages seeds packages in a repository
+func (s *Server) AddPackages(repositoryID string, packages ...*repoflow.PackageRepository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pkg := range packages {
+		if pkg.Id == "" {
+			pkg.Id = s.newID("pkg")
+		}
+	}
+	s.packages[repositoryID] = append(s.packages[repositoryID], packages...)
+}
+
+// InjectFailure makes matching requests fail
+func (s *Server) InjectFailure(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// SetLatency delays every response
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Reset removes the seeded data, the failures and the received requests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = nil
+	s.requests = nil
+	s.workspaces = nil
+	s.repositories = map[string][]*repoflow.Repository{}
+	s.packages = map[string][]*repoflow.PackageRepository{}
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /1/workspaces", s.listWorkspaces)
+	mux.HandleFunc("POST /1/workspaces", s.createWorkspace)
+	mux.HandleFunc("GET /1/workspaces/{ws}", s.getWorkspace)
+	mux.HandleFunc("DELETE /1/workspaces/{ws}", s.deleteWorkspace)
+
+	mux.HandleFunc("GET /1/workspaces/{ws}/repositories", s.listRepositories)
+	mux.HandleFunc("POST /1/workspaces/{ws}/repositories/{store}", s.createRepository)
+	mux.HandleFunc("GET /1/workspaces/{ws}/repositories/{id}", s.getRepository)
+	mux.HandleFunc("PATCH /1/workspaces/{ws}/repositories/{store}/{id}", s.updateRepository)
+	mux.HandleFunc("DELETE /1/workspaces/{ws}/repositories/{id}", s.deleteRepository)
+	mux.HandleFunc("DELETE /1/workspaces/{ws}/repositories/{id}/content", s.deleteRepositoryContent)
+	mux.HandleFunc("GET /1/workspaces/{ws}/repositories/{id}/packages", s.listPackages)
+
+	mux.HandleFunc("GET /1/info", s.serverInfo)
+	mux.HandleFunc("GET /1/user", s.currentUser)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeErrors(w, http.StatusNotFound, "Route not found")
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body.Close()
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		latency := s.latency
+		failure := s.matchFailure(r)
+		s.mu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if failure != nil {
+			for key, values := range failure.Header {
+				w.Header()[key] = values
+			}
+			writeErrors(w, failure.Status, failure.Messages...)
+			return
+		}
+
+		if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeErrors(w, http.StatusUnauthorized, "Invalid or missing personal access token")
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// matchFailure returns the injected failure matching r, s.mu must be held
+func (s *Server) matchFailure(r *http.Request) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, r.URL.Path); !ok {
+				continue
+			}
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = slices.Delete(s.failures, i, i+1)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// newID returns a deterministic identifier, s.mu must be held
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%04d", prefix, s.nextID)
+}
+
+// --- Workspaces ---
+
+func (s *Server) listWorkspaces(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := []repoflow.Workspaces{}
+	for _, ws := range s.workspaces {
+		list = append(list, repoflow.Workspaces{Id: ws.Id, Name: ws.Name})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createWorkspace(w http.ResponseWriter, r *http.Request) {
+	var opts repoflow.WorkspaceOptions
+	if !decode(w, r, &opts) {
+		return
+	}
+	if opts.Name == "" {
+		writeErrors(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ws := range s.workspaces {
+		if ws.Name == opts.Name {
+			writeErrors(w, http.StatusConflict, fmt.Sprintf("workspace %s already exists", opts.Name))
+			return
+		}
+	}
+
+	ws := &repoflow.Workspace{
+		Id:                  s.newID("ws"),
+		Name:                opts.Name,
+		PackageLimit:        opts.PackageLimit,
+		StorageLimitInByte:  opts.StorageLimit,
+		TransferLimitInByte: opts.BandwidthLimit,
+	}
+	s.workspaces = append(s.workspaces, ws)
+	writeJSON(w, http.StatusOK, ws)
+}
+
+func (s *Server) serverInfo(w http.ResponseWriter, r *http.Request) {
+	if s.Version == "" {
+		writeErrors(w, http.StatusNotFound, "Route not found")
+		return
+	}
+	w.Header().Set(repoflow.VersionHeader, s.Version)
+	writeJSON(w, http.StatusOK, map[string]any{"version": s.Version})
+}
+
+func (s *Server) currentUser(w http.ResponseWriter, r *http.Request) {
+	if s.Identity == nil {
+		writeErrors(w, http.StatusNotFound, "Route not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Identity)
+}
+
+func (s *Server) getWorkspace(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := s.findWorkspace(w, r)
+	if ws == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, ws)
+}
+
+func (s *Server) deleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := s.findWorkspace(w, r)
+	if ws == nil {
+		return
+	}
+	s.workspaces = slices.DeleteFunc(s.workspaces, func(item *repoflow.Workspace) bool { return item == ws })
+	for _, repo := range s.repositories[ws.Id] {
+		delete(s.packages, repo.Id)
+	}
+	delete(s.repositories, ws.Id)
+	writeJSON(w, http.StatusOK, ws)
+}
+
+// findWorkspace returns the workspace of the request or writes a 404, s.mu must be held
+func (s *Server) findWorkspace(w http.ResponseWriter, r *http.Request) *repoflow.Workspace {
+	id := r.PathValue("ws")
+	for _, ws := range s.workspaces {
+		if ws.Id == id {
+			return ws
+		}
+	}
+	writeErrors(w, http.StatusNotFound, fmt.Sprintf("workspace %s not found", id))
+	return nil
+}
+
+// --- Repositories ---
+
+func (s *Server) listRepositories(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := s.findWorkspace(w, r)
+	if ws == nil {
+		return
+	}
+
+	list := []repoflow.Repositories{}
+	for _, repo := range s.repositories[ws.Id] {
+		list = append(list, repoflow.Repositories{
+			Id:             repo.Id,
+			Name:           repo.Name,
+			PackageType:    repo.PackageType,
+			RepositoryType: repo.RepositoryType,
+			Status:         repo.Status,
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createRepository(w http.ResponseWriter, r *http.Request) {
+	store := r.PathValue("store")
+	if store != "local" && store != "remote" && store != "virtual" {
+		writeErrors(w, http.StatusNotFound, "Route not found")
+		return
+	}
+
+	var opts struct {
+		repoflow.RepositoryRemoteOptions
+		ChildRepositoryIds      []string `json:"childRepositoryIds"`
+		UploadLocalRepositoryId string   `json:"uploadLocalRepositoryId"`
+	}
+	if !decode(w, r, &opts) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := s.findWorkspace(w, r)
+	if ws == nil {
+		return
+	}
+
+	var errs []string
+	if opts.Name == "" {
+		errs = append(errs, "name is required")
+	}
+	if opts.PackageType == "" {
+		errs = append(errs, "packageType is required")
+	}
+	for _, repo := range s.repositories[ws.Id] {
+		if opts.Name != "" && repo.Name == opts.Name {
+			writeErrors(w, http.StatusConflict, fmt.Sprintf("repository %s already exists", opts.Name))
+			return
+		}
+	}
+
+	repo := &repoflow.Repository{
+		Id:             s.newID("repo"),
+		Name:           opts.Name,
+		RepositoryType: store,
+		PackageType:    opts.PackageType,
+		Status:         "active",
+		WorkspaceId:    ws.Id,
+	}
+
+	switch store {
+	case "remote":
+		if opts.RemoteRepositoryUrl == "" {
+			errs = append(errs, "remoteRepositoryUrl is required")
+		}
+		repo.RemoteRepositoryUrl = &opts.RemoteRepositoryUrl
+		if opts.RemoteRepositoryUsername != "" {
+			repo.RemoteRepositoryUsername = &opts.RemoteRepositoryUsername
+		}
+		repo.IsRemoteCacheEnabled = opts.IsRemoteCacheEnabled
+		repo.FileCacheTimeTillRevalidation = opts.FileCacheTimeTillRevalidation
+		repo.MetadataCacheTimeTillRevalidation = opts.MetadataCacheTimeTillRevalidation
+
+	case "virtual":
+		errs = append(errs, s.setChildren(repo, opts.ChildRepositoryIds, opts.UploadLocalRepositoryId)...)
+	}
+
+	if len(errs) > 0 {
+		writeErrors(w, http.StatusBadRequest, errs...)
+		return
+	}
+
+	s.repositories[ws.Id] = append(s.repositories[ws.Id], repo)
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) updateRepository(w http.ResponseWriter, r *http.Request) {
+	var fields map[string]json.RawMessage
+	if !decode(w, r, &fields) {
+		return
+	}
+	var opts struct {
+		Name                     *string  `json:"name"`
+		RemoteRepositoryUrl      *string  `json:"remoteRepositoryUrl"`
+		IsRemoteCacheEnabled     *bool    `json:"isRemoteCacheEnabled"`
+		RemoteRepositoryUsername *string  `json:"remoteRepositoryUsername"`
+		ChildRepositoryIds       []string `json:"childRepositoryIds"`
+		UploadLocalRepositoryId  *string  `json:"uploadLocalRepositoryId"`
+	}
+	data, _ := json.Marshal(fields)
+	json.Unmarshal(data, &opts)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.findRepository(w, r)
+	if repo == nil {
+		return
+	}
+	if store := r.PathValue("store"); repo.RepositoryType != store {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("repository %s is not a %s repository", repo.Id, store))
+		return
+	}
+
+	updated := *repo
+	var errs []string
+	if opts.Name != nil {
+		if *opts.Name == "" {
+			errs = append(errs, "name cannot be empty")
+		}
+		for _, other := range s.repositories[repo.WorkspaceId] {
+			if other != repo && other.Name == *opts.Name {
+				writeErrors(w, http.StatusConflict, fmt.Sprintf("repository %s already exists", *opts.Name))
+				return
+			}
+		}
+		updated.Name = *opts.Name
+	}
+
+	switch repo.RepositoryType {
+	case "remote":
+		if opts.RemoteRepositoryUrl != nil {
+			if *opts.RemoteRepositoryUrl == "" {
+				errs = append(errs, "remoteRepositoryUrl cannot be empty")
+			}
+			updated.RemoteRepositoryUrl = opts.RemoteRepositoryUrl
+		}
+		if opts.RemoteRepositoryUsername != nil {
+			updated.RemoteRepositoryUsername = opts.RemoteRepositoryUsername
+		}
+		if opts.IsRemoteCacheEnabled != nil {
+			updated.IsRemoteCacheEnabled = *opts.IsRemoteCacheEnabled
+		}
+		// null restores indefinite caching
+		for key, field := range map[string]**int{
+			"fileCacheTimeTillRevalidation":     &updated.FileCacheTimeTillRevalidation,
+			"metadataCacheTimeTillRevalidation": &updated.MetadataCacheTimeTillRevalidation,
+		} {
+			if raw, ok := fields[key]; ok {
+				var value *int
+				json.Unmarshal(raw, &value)
+				*field = value
+			}
+		}
+
+	case "virtual":
+		if opts.ChildRepositoryIds != nil || opts.UploadLocalRepositoryId != nil {
+			children := opts.ChildRepositoryIds
+			if children == nil {
+				for _, child := range repo.ChildRepositories {
+					children = append(children, child.Id)
+				}
+			}
+			upload := ""
+			if repo.UploadLocalRepositoryId != nil {
+				upload = *repo.UploadLocalRepositoryId
+			}
+			if opts.UploadLocalRepositoryId != nil {
+				upload = *opts.UploadLocalRepositoryId
+			}
+			errs = append(errs, s.setChildren(&updated, children, upload)...)
+		}
+	}
+
+	if len(errs) > 0 {
+		writeErrors(w, http.StatusBadRequest, errs...)
+		return
+	}
+
+	*repo = updated
+	writeJSON(w, http.StatusOK, repo)
+}
+
+// setChildren validates and sets the children and upload target of a
+// virtual repository, s.mu must be held
+func (s *Server) setChildren(repo *repoflow.Repository, childIDs []string, uploadID string) []string {
+	var errs []string
+	if len(childIDs) == 0 {
+		errs = append(errs, "childRepositoryIds is required")
+	}
+
+	repo.ChildRepositories = nil
+	for _, id := range childIDs {
+		child := s.repository(repo.WorkspaceId, id)
+		switch {
+		case child == nil:
+			errs = append(errs, fmt.Sprintf("child repository %s not found", id))
+		case child.PackageType != repo.PackageType:
+			errs = append(errs, fmt.Sprintf("child repository %s must be of type %s", id, repo.PackageType))
+		default:
+			repo.ChildRepositories = append(repo.ChildRepositories, repoflow.ChildRepository{Id: child.Id, Name: child.Name})
+		}
+	}
+
+	repo.UploadLocalRepositoryId = nil
+	repo.UploadTargetLocalRepository = repoflow.UploadTargetLocalRepository{}
+	if uploadID != "" {
+		upload := s.repository(repo.WorkspaceId, uploadID)
+		if upload == nil || upload.RepositoryType != "local" || !slices.Contains(childIDs, upload.Id) {
+			errs = append(errs, "uploadLocalRepositoryId must be a local child repository")
+		} else {
+			repo.UploadLocalRepositoryId = &upload.Id
+			repo.UploadTargetLocalRepository = repoflow.UploadTargetLocalRepository{Id: upload.Id, Name: upload.Name}
+		}
+	}
+	return errs
+}
+
+func (s *Server) getRepository(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.findRepository(w, r)
+	if repo == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) deleteRepository(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.findRepository(w, r)
+	if repo == nil {
+		return
+	}
+	s.repositories[repo.WorkspaceId] = slices.DeleteFunc(s.repositories[repo.WorkspaceId], func(item *repoflow.Repository) bool {
+		return item == repo
+	})
+	delete(s.packages, repo.Id)
+	writeJSON(w, http.StatusOK, repoflow.RepostotryDelete{RepositoryId: repo.Id, Status: "deleted"})
+}
+
+func (s *Server) deleteRepositoryContent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.findRepository(w, r)
+	if repo == nil {
+		return
+	}
+	delete(s.packages, repo.Id)
+	writeJSON(w, http.StatusOK, repoflow.RepostotryDelete{RepositoryId: repo.Id, Status: "deleted"})
+}
+
+func (s *Server) listPackages(w http.ResponseWriter, r *http.Request) {
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := queryInt(r, "limit", repoflow.DefaultPageSize)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.findRepository(w, r)
+	if repo == nil {
+		return
+	}
+
+	all := s.packages[repo.Id]
+	start := min(offset, len(all))
+	end := min(start+limit, len(all))
+	writeJSON(w, http.StatusOK, repoflow.RepositoryPackages{
+		Total:    len(all),
+		Offset:   offset,
+		Limit:    limit,
+		Packages: append([]*repoflow.PackageRepository{}, all[start:end]...),
+	})
+}
+
+// repository returns a repository of a workspace by ID, s.mu must be held
+func (s *Server) repository(workspaceID, id string) *repoflow.Repository {
+	for _, repo := range s.repositories[workspaceID] {
+		if repo.Id == id {
+			return repo
+		}
+	}
+	return nil
+}
+
+// findRepository returns the repository of the request or writes a 404, s.mu must be held
+func (s *Server) findRepository(w http.ResponseWriter, r *http.Request) *repoflow.Repository {
+	ws := s.findWorkspace(w, r)
+	if ws == nil {
+		return nil
+	}
+	id := r.PathValue("id")
+	if repo := s.repository(ws.Id, id); repo != nil {
+		return repo
+	}
+	writeErrors(w, http.StatusNotFound, fmt.Sprintf("repository %s not found", id))
+	return nil
+}
+
+// --- Helpers ---
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return false
+	}
+	return true
+}
+
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", key)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeErrors(w http.ResponseWriter, status int, messages ...string) {
+	if len(messages) == 0 {
+		messages = []string{http.StatusText(status)}
+	}
+	writeJSON(w, status, repoflow.APIErrors{Errors: messages})
+}
@@ -0,0 +1,341 @@
+package repoflowtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+func newServer(t *testing.T) *repoflowtest.Server {
+	srv := repoflowtest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWorkspaces(t *testing.T) {
+	srv := newServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	ws, err := client.CreateWorkspace(ctx, repoflow.WorkspaceOptions{Name: "team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetWorkspace(ctx, ws.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "team" {
+		t.Errorf("workspace = %+v", got)
+	}
+
+	_, err = client.CreateWorkspace(ctx, repoflow.WorkspaceOptions{Name: "team"})
+	if !errors.Is(err, repoflow.ErrConflict) {
+		t.Errorf("duplicate workspace error = %v, want ErrConflict", err)
+	}
+
+	if _, err := client.DeleteWorkspace(ctx, ws.Id); err != nil {
+		t.Fatal(err)
+	}
+	list, err := client.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*list) != 0 {
+		t.Errorf("workspaces after delete = %+v", *list)
+	}
+	if _, err := client.GetWorkspace(ctx, ws.Id); !errors.Is(err, repoflow.ErrNotFound) {
+		t.Errorf("deleted workspace error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRepositories(t *testing.T) {
+	srv := newServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+	ws := srv.AddWorkspace("team")
+
+	local, err := client.CreateLocalRepository(ctx, ws.Id, repoflow.RepositoryOptions{Name: "npm-local", PackageType: "npm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := client.CreateRemoteRepository(ctx, ws.Id, repoflow.RepositoryRemoteOptions{
+		Name:                "npm-remote",
+		PackageType:         "npm",
+		RemoteRepositoryUrl: "https://registry.npmjs.org",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	virtual, err := client.CreateVirtualRepository(ctx, ws.Id, repoflow.RepositoryVirtualOptions{
+		Name:                    "npm",
+		PackageType:             "npm",
+		ChildRepositoryIds:      []string{local.Id, remote.Id},
+		UploadLocalRepositoryId: local.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(virtual.ChildRepositories) != 2 || virtual.UploadTargetLocalRepository.Id != local.Id {
+		t.Errorf("virtual repository = %+v", virtual)
+	}
+
+	name := "npm-internal"
+	updated, err := client.UpdateLocalRepository(ctx, ws.Id, local.Id, repoflow.RepositoryUpdateOptions{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != name {
+		t.Errorf("updated repository = %+v", updated)
+	}
+
+	list, err := client.ListRepositories(ctx, ws.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*list) != 3 {
+		t.Errorf("repositories = %+v", *list)
+	}
+
+	if _, err := client.DeleteRepository(ctx, ws.Id, remote.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetRepository(ctx, ws.Id, remote.Id); !errors.Is(err, repoflow.ErrNotFound) {
+		t.Errorf("deleted repository error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRepositoryValidation(t *testing.T) {
+	srv := newServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+	ws := srv.AddWorkspace("team")
+	maven := srv.AddRepository(ws.Id, repoflow.Repository{Name: "maven", PackageType: "maven", RepositoryType: "local"})
+
+	tests := []struct {
+		name     string
+		store    string
+		opts     any
+		status   int
+		messages []string
+	}{
+		{"missing fields", "local", repoflow.RepositoryOptions{}, http.StatusBadRequest,
+			[]string{"name is required", "packageType is required"}},
+		{"remote without url", "remote", repoflow.RepositoryRemoteOptions{Name: "r", PackageType: "npm"}, http.StatusBadRequest,
+			[]string{"remoteRepositoryUrl is required"}},
+		{"child of another type", "virtual", repoflow.RepositoryVirtualOptions{Name: "v", PackageType: "npm", ChildRepositoryIds: []string{maven.Id}}, http.StatusBadRequest,
+			[]string{"child repository " + maven.Id + " must be of type npm"}},
+		{"duplicate name", "local", repoflow.RepositoryOptions{Name: "maven", PackageType: "maven"}, http.StatusConflict,
+			[]string{"repository maven already exists"}},
+		{"unknown store", "other", repoflow.RepositoryOptions{Name: "o", PackageType: "npm"}, http.StatusNotFound,
+			[]string{"Route not found"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateRepository(ctx, ws.Id, tt.store, tt.opts)
+			var apiErr *repoflow.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *repoflow.Error", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if len(apiErr.Messages) != len(tt.messages) {
+				t.Fatalf("messages = %q, want %q", apiErr.Messages, tt.messages)
+			}
+			for i, message := range tt.messages {
+				if apiErr.Messages[i] != message {
+					t.Errorf("message %d = %q, want %q", i, apiErr.Messages[i], message)
+				}
+			}
+		})
+	}
+}
+
+func TestPackagesPagination(t *testing.T) {
+	srv := newServer(t)
+	client := srv.Client()
+	ws := srv.AddWorkspace("team")
+	repo := srv.AddRepository(ws.Id, repoflow.Repository{Name: "npm", PackageType: "npm", RepositoryType: "local"})
+	for range 5 {
+		srv.AddPackages(repo.Id, &repoflow.PackageRepository{Name: "pkg"})
+	}
+
+	var ids []string
+	for pkg, err := range client.AllRepositoryPackages(context.Background(), ws.Id, repo.Id, &repoflow.ListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, pkg.Id)
+	}
+	if len(ids) != 5 {
+		t.Errorf("packages = %v, want 5", ids)
+	}
+
+	pages := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "/1/workspaces/"+ws.Id+"/repositories/"+repo.Id+"/packages" {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("page requests = %d, want 3", pages)
+	}
+}
+
+func TestInjectFailure(t *testing.T) {
+	srv := newServer(t)
+	ws := srv.AddWorkspace("team")
+	srv.InjectFailure(repoflowtest.Failure{
+		Method:   http.MethodGet,
+		Path:     "/1/workspaces/*",
+		Status:   http.StatusTooManyRequests,
+		Messages: []string{"slow down"},
+		Header:   http.Header{"Retry-After": {"0"}},
+		Times:    2,
+	})
+	ctx := context.Background()
+
+	// Without retries the failures are returned
+	client := srv.Client()
+	_, err := client.GetWorkspace(ctx, ws.Id)
+	if !errors.Is(err, repoflow.ErrRateLimited) {
+		t.Fatalf("error = %v, want ErrRateLimited", err)
+	}
+	var apiErr *repoflow.Error
+	if errors.As(err, &apiErr) && (len(apiErr.Messages) != 1 || apiErr.Messages[0] != "slow down") {
+		t.Errorf("messages = %q", apiErr.Messages)
+	}
+	if _, err := client.ListWorkspaces(ctx); err != nil {
+		t.Errorf("unmatched path failed: %v", err)
+	}
+
+	// The second failure is retried, then the failure is exhausted
+	client = srv.Client(repoflow.WithRetryPolicy(&repoflow.RetryPolicy{MaxAttempts: 2}))
+	if _, err := client.GetWorkspace(ctx, ws.Id); err != nil {
+		t.Errorf("retried request failed: %v", err)
+	}
+}
+
+func TestToken(t *testing.T) {
+	srv := newServer(t)
+	srv.Token = "secret"
+	ctx := context.Background()
+
+	if _, err := srv.Client().ListWorkspaces(ctx); err != nil {
+		t.Errorf("valid token: %v", err)
+	}
+	_, err := srv.Client(repoflow.WithToken("wrong")).ListWorkspaces(ctx)
+	if !errors.Is(err, repoflow.ErrUnauthorized) {
+		t.Errorf("invalid token error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	srv := newServer(t)
+	srv.Version = "1.2.3"
+	srv.Identity = &repoflow.Identity{Id: "user-1", Username: "admin"}
+
+	info, err := srv.Client().ServerInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "1.2.3" || info.Identity == nil || info.Identity.Username != "admin" {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestServerInfoPartialFailure(t *testing.T) {
+	srv := newServer(t)
+	srv.Version = "1.2.3"
+	srv.Identity = &repoflow.Identity{Id: "user-1", Username: "admin"}
+	srv.InjectFailure(repoflowtest.Failure{Path: repoflow.CurrentUserEndpoint, Status: http.StatusInternalServerError})
+
+	info, err := srv.Client().ServerInfo(context.Background())
+	var apiErr *repoflow.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("error = %v, want the 500", err)
+	}
+	if info == nil || info.Version != "1.2.3" || info.Identity != nil {
+		t.Errorf("info = %+v, want the version gathered before the failure", info)
+	}
+}
+
+func TestServerInfoBypassesCache(t *testing.T) {
+	srv := newServer(t)
+	srv.Version = "1.2.3"
+	srv.Identity = &repoflow.Identity{Id: "user-1", Username: "admin"}
+	client := srv.Client(repoflow.WithCache(repoflow.NewCache(t.TempDir(), time.Hour)))
+	ctx := context.Background()
+
+	if _, err := client.ServerInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	srv.Version = "2.0.0"
+	info, err := client.ServerInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "2.0.0" {
+		t.Errorf("version = %s, want the fresh 2.0.0", info.Version)
+	}
+	for _, req := range srv.Requests() {
+		if req.Header.Get("Cache-Control") != "no-cache" {
+			t.Errorf("%s %s sent without no-cache", req.Method, req.Path)
+		}
+	}
+}
+
+func TestVirtualManagerAndCloner(t *testing.T) {
+	srv := newServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+	src := srv.AddWorkspace("src")
+	dst := srv.AddWorkspace("dst")
+
+	for _, ws := range []string{src.Id, dst.Id} {
+		srv.AddRepository(ws, repoflow.Repository{Name: "npm-local", PackageType: "npm", RepositoryType: "local"})
+	}
+	local := srv.AddRepository(src.Id, repoflow.Repository{Name: "npm-other", PackageType: "npm", RepositoryType: "local"})
+	list, _ := client.ListRepositories(ctx, src.Id)
+	virtual, err := client.CreateVirtualRepository(ctx, src.Id, repoflow.RepositoryVirtualOptions{
+		Name:               "npm",
+		PackageType:        "npm",
+		ChildRepositoryIds: []string{(*list)[0].Id},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := client.NewVirtualManager()
+	virtual, err = manager.AddChildren(ctx, src.Id, virtual.Id, local.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(virtual.ChildRepositories) != 2 {
+		t.Errorf("children = %+v", virtual.ChildRepositories)
+	}
+
+	// npm-other has no counterpart in dst
+	_, err = client.NewCloner().Clone(ctx, virtual, dst.Id, repoflow.CloneOptions{})
+	if !errors.Is(err, repoflow.ErrNoCounterpart) {
+		t.Errorf("clone error = %v, want ErrNoCounterpart", err)
+	}
+
+	if virtual, err = manager.RemoveChildren(ctx, src.Id, virtual.Id, local.Id); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := client.NewCloner().Clone(ctx, virtual, dst.Id, repoflow.CloneOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.WorkspaceId != dst.Id || len(clone.ChildRepositories) != 1 || clone.ChildRepositories[0].Name != "npm-local" {
+		t.Errorf("clone = %+v", clone)
+	}
+}
@@ -1,7 +1,10 @@
 package repoflow
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 )
 
@@ -59,7 +62,7 @@ type RepositoryOptions struct {
 type RepositoryPackages struct {
 	Total    int                  `json:"total"`
 	Offset   int                  `json:"offset"`
-	Limit    int                  `json:"int"`
+	Limit    int                  `json:"limit"`
 	Packages []*PackageRepository `json:"packages"`
 }
 
@@ -83,6 +86,55 @@ type RepositoryVirtualOptions struct {
 	UploadLocalRepositoryId string   `json:"uploadLocalRepositoryId,omitempty"`
 }
 
+// RepositoryUpdateOptions defines the partial payload for updating a local
+// repository, nil fields are left unchanged
+type RepositoryUpdateOptions struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// RepositoryRemoteUpdateOptions defines the partial payload for updating a
+// remote repository, nil fields are left unchanged.
+// A negative revalidation time restores indefinite caching.
+type RepositoryRemoteUpdateOptions struct {
+	Name                              *string `json:"name,omitempty"`
+	RemoteRepositoryUrl               *string `json:"remoteRepositoryUrl,omitempty"`
+	IsRemoteCacheEnabled              *bool   `json:"isRemoteCacheEnabled,omitempty"`
+	RemoteRepositoryUsername          *string `json:"remoteRepositoryUsername,omitempty"`
+	RemoteRepositoryPassword          *string `json:"remoteRepositoryPassword,omitempty"`
+	FileCacheTimeTillRevalidation     *int    `json:"fileCacheTimeTillRevalidation,omitempty"`
+	MetadataCacheTimeTillRevalidation *int    `json:"metadataCacheTimeTillRevalidation,omitempty"`
+}
+
+// MarshalJSON sends negative revalidation times as null
+func (o RepositoryRemoteUpdateOptions) MarshalJSON() ([]byte, error) {
+	type options RepositoryRemoteUpdateOptions
+	data, err := json.Marshal(options(o))
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if o.FileCacheTimeTillRevalidation != nil && *o.FileCacheTimeTillRevalidation < 0 {
+		fields["fileCacheTimeTillRevalidation"] = json.RawMessage("null")
+	}
+	if o.MetadataCacheTimeTillRevalidation != nil && *o.MetadataCacheTimeTillRevalidation < 0 {
+		fields["metadataCacheTimeTillRevalidation"] = json.RawMessage("null")
+	}
+	return json.Marshal(fields)
+}
+
+// RepositoryVirtualUpdateOptions defines the partial payload for updating a
+// virtual repository, nil fields are left unchanged.
+// An empty UploadLocalRepositoryId removes the upload target.
+type RepositoryVirtualUpdateOptions struct {
+	Name                    *string   `json:"name,omitempty"`
+	ChildRepositoryIds      *[]string `json:"childRepositoryIds,omitempty"`
+	UploadLocalRepositoryId *string   `json:"uploadLocalRepositoryId,omitempty"`
+}
+
 type RepostotryDelete struct {
 	RepositoryId string `json:"repositoryId"`
 	Status       string `json:"status"`
@@ -90,81 +142,204 @@ type RepostotryDelete struct {
 
 // ListRepositories retrieves all available repository
 // GET /1/workspaces/:workspace/repositories
-func (c *Client) ListRepositories(workspace string) (*[]Repositories, error) {
+func (c *Client) ListRepositories(ctx context.Context, workspace string) (*[]Repositories, error) {
+	rep, _, err := c.ListRepositoriesWithResponse(ctx, workspace)
+	return rep, err
+}
+
+// ListRepositoriesWithResponse is ListRepositories also returning the response metadata
+func (c *Client) ListRepositoriesWithResponse(ctx context.Context, workspace string) (*[]Repositories, *Response, error) {
 	var rep []Repositories
 	endpoint := fmt.Sprintf("%s/%s%s", WorkspacesEndpoint, workspace, RepositoryEndpoint)
-	err := c.DoRequest(http.MethodGet, endpoint, nil, &rep)
-	return &rep, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodGet, endpoint, nil, &rep)
+	return &rep, resp, err
 }
 
 // GetRepository retrieves metadata for a specific repository
 // GET /1/workspaces/:workspace/repositories/:id
-func (c *Client) GetRepository(workspace string, id string) (*Repository, error) {
+func (c *Client) GetRepository(ctx context.Context, workspace string, id string) (*Repository, error) {
+	rep, _, err := c.GetRepositoryWithResponse(ctx, workspace, id)
+	return rep, err
+}
+
+// GetRepositoryWithResponse is GetRepository also returning the response metadata
+func (c *Client) GetRepositoryWithResponse(ctx context.Context, workspace string, id string) (*Repository, *Response, error) {
 	var rep Repository
 	endpoint := fmt.Sprintf("%s/%s%s/%s", WorkspacesEndpoint, workspace, RepositoryEndpoint, id)
-	err := c.DoRequest(http.MethodGet, endpoint, nil, &rep)
-	return &rep, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodGet, endpoint, nil, &rep)
+	return &rep, resp, err
 }
 
-// ListRepositoryPackages list all available package in a repository
+// ListRepositoryPackages list one page of packages available in a repository
 // GET /1/workspaces/:workspace/repositories/:id/packages
-func (c *Client) ListRepositoryPackages(workspace string, id string) (*RepositoryPackages, error) {
+func (c *Client) ListRepositoryPackages(ctx context.Context, workspace string, id string, opts *ListOptions) (*RepositoryPackages, error) {
+	rep, _, err := c.ListRepositoryPackagesWithResponse(ctx, workspace, id, opts)
+	return rep, err
+}
+
+// ListRepositoryPackagesWithResponse is ListRepositoryPackages also returning the response metadata
+func (c *Client) ListRepositoryPackagesWithResponse(ctx context.Context, workspace string, id string, opts *ListOptions) (*RepositoryPackages, *Response, error) {
 	var rep RepositoryPackages
-	endpoint := fmt.Sprintf("%s/%s%s/%s/packages", WorkspacesEndpoint, workspace, RepositoryEndpoint, id)
-	err := c.DoRequest(http.MethodGet, endpoint, nil, &rep)
-	return &rep, err
+	endpoint := fmt.Sprintf("%s/%s%s/%s/packages%s", WorkspacesEndpoint, workspace, RepositoryEndpoint, id, opts.query())
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodGet, endpoint, nil, &rep)
+	if err == nil {
+		resp.setPage(rep.Offset, rep.Limit, len(rep.Packages), rep.Total)
+	}
+	return &rep, resp, err
+}
+
+// AllRepositoryPackages iterates over all packages of a repository, fetching
+// the pages on demand. opts sets the starting offset and the page size.
+func (c *Client) AllRepositoryPackages(ctx context.Context, workspace string, id string, opts *ListOptions) iter.Seq2[*PackageRepository, error] {
+	return func(yield func(*PackageRepository, error) bool) {
+		page := ListOptions{Limit: DefaultPageSize}
+		if opts != nil {
+			page = *opts
+			if page.Limit <= 0 {
+				page.Limit = DefaultPageSize
+			}
+		}
+
+		for {
+			rep, err := c.ListRepositoryPackages(ctx, workspace, id, &page)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, pkg := range rep.Packages {
+				if !yield(pkg, nil) {
+					return
+				}
+			}
+
+			page.Offset += len(rep.Packages)
+			if len(rep.Packages) == 0 || page.Offset >= rep.Total {
+				return
+			}
+		}
+	}
 }
 
 // CreateRepository create a new repository with the given options
 // POST /1/workspaces/:workspace/repositories/:store
-func (c *Client) CreateRepository(workspace string, store string, opts any) (*Repository, error) {
+func (c *Client) CreateRepository(ctx context.Context, workspace string, store string, opts any) (*Repository, error) {
+	rep, _, err := c.CreateRepositoryWithResponse(ctx, workspace, store, opts)
+	return rep, err
+}
+
+// CreateRepositoryWithResponse is CreateRepository also returning the response metadata
+func (c *Client) CreateRepositoryWithResponse(ctx context.Context, workspace string, store string, opts any) (*Repository, *Response, error) {
 	var rep Repository
 	endpoint := fmt.Sprintf("%s/%s%s/%s", WorkspacesEndpoint, workspace, RepositoryEndpoint, store)
-	err := c.DoRequest(http.MethodPost, endpoint, opts, &rep)
-	return &rep, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodPost, endpoint, opts, &rep)
+	return &rep, resp, err
 }
 
 // CreateLocalRepository create a new repository with the given options
 // POST /1/workspaces/:workspace/repositories/local
-func (c *Client) CreateLocalRepository(workspace string, opts RepositoryOptions) (*Repository, error) {
+func (c *Client) CreateLocalRepository(ctx context.Context, workspace string, opts RepositoryOptions) (*Repository, error) {
 	var rep Repository
 	endpoint := fmt.Sprintf("%s/%s%s/local", WorkspacesEndpoint, workspace, RepositoryEndpoint)
-	err := c.DoRequest(http.MethodPost, endpoint, opts, &rep)
+	err := c.DoRequest(ctx, http.MethodPost, endpoint, opts, &rep)
 	return &rep, err
 }
 
 // CreateRemoteRepository create a new repository with the given options
 // POST /1/workspaces/:workspace/repositories/remote
-func (c *Client) CreateRemoteRepository(workspace string, opts RepositoryRemoteOptions) (*Repository, error) {
+func (c *Client) CreateRemoteRepository(ctx context.Context, workspace string, opts RepositoryRemoteOptions) (*Repository, error) {
 	var rep Repository
 	endpoint := fmt.Sprintf("%s/%s%s/remote", WorkspacesEndpoint, workspace, RepositoryEndpoint)
-	err := c.DoRequest(http.MethodPost, endpoint, opts, &rep)
+	err := c.DoRequest(ctx, http.MethodPost, endpoint, opts, &rep)
 	return &rep, err
 }
 
 // CreateVirtualRepository create a new repository with the given options
 // POST /1/workspaces/:workspace/repositories/virtual
-func (c *Client) CreateVirtualRepository(workspace string, opts RepositoryVirtualOptions) (*Repository, error) {
+func (c *Client) CreateVirtualRepository(ctx context.Context, workspace string, opts RepositoryVirtualOptions) (*Repository, error) {
 	var rep Repository
 	endpoint := fmt.Sprintf("%s/%s%s/virtual", WorkspacesEndpoint, workspace, RepositoryEndpoint)
-	err := c.DoRequest(http.MethodPost, endpoint, opts, &rep)
+	err := c.DoRequest(ctx, http.MethodPost, endpoint, opts, &rep)
 	return &rep, err
 }
 
+// UpdateRepository updates a repository with the given partial options
+// PATCH /1/workspaces/:workspace/repositories/:store/:id
+func (c *Client) UpdateRepository(ctx context.Context, workspace string, store string, id string, opts any) (*Repository, error) {
+	rep, _, err := c.UpdateRepositoryWithResponse(ctx, workspace, store, id, opts)
+	return rep, err
+}
+
+// UpdateRepositoryWithResponse is UpdateRepository also returning the response metadata
+func (c *Client) UpdateRepositoryWithResponse(ctx context.Context, workspace string, store string, id string, opts any) (*Repository, *Response, error) {
+	var rep Repository
+	endpoint := fmt.Sprintf("%s/%s%s/%s/%s", WorkspacesEndpoint, workspace, RepositoryEndpoint, store, id)
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodPatch, endpoint, opts, &rep)
+	return &rep, resp, err
+}
+
+// UpdateLocalRepository updates a local repository with the given partial options
+// PATCH /1/workspaces/:workspace/repositories/local/:id
+func (c *Client) UpdateLocalRepository(ctx context.Context, workspace string, id string, opts RepositoryUpdateOptions) (*Repository, error) {
+	rep, _, err := c.UpdateLocalRepositoryWithResponse(ctx, workspace, id, opts)
+	return rep, err
+}
+
+// UpdateLocalRepositoryWithResponse is UpdateLocalRepository also returning the response metadata
+func (c *Client) UpdateLocalRepositoryWithResponse(ctx context.Context, workspace string, id string, opts RepositoryUpdateOptions) (*Repository, *Response, error) {
+	return c.UpdateRepositoryWithResponse(ctx, workspace, "local", id, opts)
+}
+
+// UpdateRemoteRepository updates a remote repository with the given partial options
+// PATCH /1/workspaces/:workspace/repositories/remote/:id
+func (c *Client) UpdateRemoteRepository(ctx context.Context, workspace string, id string, opts RepositoryRemoteUpdateOptions) (*Repository, error) {
+	rep, _, err := c.UpdateRemoteRepositoryWithResponse(ctx, workspace, id, opts)
+	return rep, err
+}
+
+// UpdateRemoteRepositoryWithResponse is UpdateRemoteRepository also returning the response metadata
+func (c *Client) UpdateRemoteRepositoryWithResponse(ctx context.Context, workspace string, id string, opts RepositoryRemoteUpdateOptions) (*Repository, *Response, error) {
+	return c.UpdateRepositoryWithResponse(ctx, workspace, "remote", id, opts)
+}
+
+// UpdateVirtualRepository updates a virtual repository with the given partial options
+// PATCH /1/workspaces/:workspace/repositories/virtual/:id
+func (c *Client) UpdateVirtualRepository(ctx context.Context, workspace string, id string, opts RepositoryVirtualUpdateOptions) (*Repository, error) {
+	rep, _, err := c.UpdateVirtualRepositoryWithResponse(ctx, workspace, id, opts)
+	return rep, err
+}
+
+// UpdateVirtualRepositoryWithResponse is UpdateVirtualRepository also returning the response metadata
+func (c *Client) UpdateVirtualRepositoryWithResponse(ctx context.Context, workspace string, id string, opts RepositoryVirtualUpdateOptions) (*Repository, *Response, error) {
+	return c.UpdateRepositoryWithResponse(ctx, workspace, "virtual", id, opts)
+}
+
 // DeleteRepository removes a workspace by its ID
 // DELETE /1/workspaces/:id/repositories/:id
-func (c *Client) DeleteRepository(workspace string, id string) (*RepostotryDelete, error) {
+func (c *Client) DeleteRepository(ctx context.Context, workspace string, id string) (*RepostotryDelete, error) {
+	rep, _, err := c.DeleteRepositoryWithResponse(ctx, workspace, id)
+	return rep, err
+}
+
+// DeleteRepositoryWithResponse is DeleteRepository also returning the response metadata
+func (c *Client) DeleteRepositoryWithResponse(ctx context.Context, workspace string, id string) (*RepostotryDelete, *Response, error) {
 	var rep RepostotryDelete
 	endpoint := fmt.Sprintf("%s/%s%s/%s", WorkspacesEndpoint, workspace, RepositoryEndpoint, id)
-	err := c.DoRequest(http.MethodDelete, endpoint, nil, &rep)
-	return &rep, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodDelete, endpoint, nil, &rep)
+	return &rep, resp, err
 }
 
 // DeleteRepositoryContent removes a workspace by its ID
 // DELETE /1/workspaces/:id/repositories/:id
-func (c *Client) DeleteRepositoryContent(workspace string, id string) (*RepostotryDelete, error) {
+func (c *Client) DeleteRepositoryContent(ctx context.Context, workspace string, id string) (*RepostotryDelete, error) {
+	rep, _, err := c.DeleteRepositoryContentWithResponse(ctx, workspace, id)
+	return rep, err
+}
+
+// DeleteRepositoryContentWithResponse is DeleteRepositoryContent also returning the response metadata
+func (c *Client) DeleteRepositoryContentWithResponse(ctx context.Context, workspace string, id string) (*RepostotryDelete, *Response, error) {
 	var rep RepostotryDelete
 	endpoint := fmt.Sprintf("%s/%s%s/%s/content", WorkspacesEndpoint, workspace, RepositoryEndpoint, id)
-	err := c.DoRequest(http.MethodDelete, endpoint, nil, &rep)
-	return &rep, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodDelete, endpoint, nil, &rep)
+	return &rep, resp, err
 }
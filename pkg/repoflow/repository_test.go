@@ -0,0 +1,107 @@
+package repoflow
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateRepository(t *testing.T) {
+	var method, path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(RequestIDHeader, "req-1")
+		io.WriteString(w, `{"id":"r1","name":"renamed"}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	ctx := context.Background()
+	name := "renamed"
+	ttr := -1
+	upload := ""
+
+	tests := []struct {
+		name     string
+		update   func() (*Repository, *Response, error)
+		wantPath string
+		wantBody string
+	}{
+		{"local", func() (*Repository, *Response, error) {
+			return client.UpdateLocalRepositoryWithResponse(ctx, "ws", "r1", RepositoryUpdateOptions{Name: &name})
+		}, "/1/workspaces/ws/repositories/local/r1", `{"name":"renamed"}`},
+		{"local without changes", func() (*Repository, *Response, error) {
+			return client.UpdateLocalRepositoryWithResponse(ctx, "ws", "r1", RepositoryUpdateOptions{})
+		}, "/1/workspaces/ws/repositories/local/r1", `{}`},
+		{"remote", func() (*Repository, *Response, error) {
+			return client.UpdateRemoteRepositoryWithResponse(ctx, "ws", "r1", RepositoryRemoteUpdateOptions{FileCacheTimeTillRevalidation: &ttr})
+		}, "/1/workspaces/ws/repositories/remote/r1", `{"fileCacheTimeTillRevalidation":null}`},
+		{"virtual children cleared", func() (*Repository, *Response, error) {
+			return client.UpdateVirtualRepositoryWithResponse(ctx, "ws", "r1", RepositoryVirtualUpdateOptions{ChildRepositoryIds: &[]string{}})
+		}, "/1/workspaces/ws/repositories/virtual/r1", `{"childRepositoryIds":[]}`},
+		{"virtual upload target removed", func() (*Repository, *Response, error) {
+			return client.UpdateVirtualRepositoryWithResponse(ctx, "ws", "r1", RepositoryVirtualUpdateOptions{UploadLocalRepositoryId: &upload})
+		}, "/1/workspaces/ws/repositories/virtual/r1", `{"uploadLocalRepositoryId":""}`},
+		{"any store", func() (*Repository, *Response, error) {
+			return client.UpdateRepositoryWithResponse(ctx, "ws", "local", "r1", map[string]string{"name": name})
+		}, "/1/workspaces/ws/repositories/local/r1", `{"name":"renamed"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, resp, err := tt.update()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method != http.MethodPatch || path != tt.wantPath {
+				t.Errorf("request = %s %s, want PATCH %s", method, path, tt.wantPath)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+			if repo.Id != "r1" || repo.Name != "renamed" {
+				t.Errorf("repository = %+v", repo)
+			}
+			if resp.RequestID != "req-1" {
+				t.Errorf("request ID = %q, want req-1", resp.RequestID)
+			}
+		})
+	}
+}
+
+func TestRepositoryRemoteUpdateOptionsJSON(t *testing.T) {
+	url := "https://registry.npmjs.org"
+	zero, positive, negative := 0, 60, -1
+
+	tests := []struct {
+		name string
+		opts RepositoryRemoteUpdateOptions
+		want string
+	}{
+		{"empty", RepositoryRemoteUpdateOptions{}, `{}`},
+		{"unrelated field", RepositoryRemoteUpdateOptions{RemoteRepositoryUrl: &url}, `{"remoteRepositoryUrl":"https://registry.npmjs.org"}`},
+		{"zero", RepositoryRemoteUpdateOptions{FileCacheTimeTillRevalidation: &zero}, `{"fileCacheTimeTillRevalidation":0}`},
+		{"positive", RepositoryRemoteUpdateOptions{MetadataCacheTimeTillRevalidation: &positive}, `{"metadataCacheTimeTillRevalidation":60}`},
+		{"negative", RepositoryRemoteUpdateOptions{
+			FileCacheTimeTillRevalidation:     &negative,
+			MetadataCacheTimeTillRevalidation: &negative,
+		}, `{"fileCacheTimeTillRevalidation":null,"metadataCacheTimeTillRevalidation":null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("json = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
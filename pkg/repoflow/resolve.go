@@ -0,0 +1,242 @@
+package repoflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrAmbiguous is returned when a name matches several resources
+var ErrAmbiguous = errors.New("ambiguous name")
+
+// Resolver turns workspace and repository references, either an ID or a
+// name, into the resources they designate. A reference is first fetched as
+// an ID, then looked up by name in the list, which is requested without
+// cache at most once per workspace. Resolved references are kept, so the
+// concurrent workers of a bulk operation share the lookups without waiting
+// on each other's requests. A resolver should live for a single invocation
+// to avoid stale results.
+type Resolver struct {
+	workspaces   WorkspaceService
+	repositories RepositoryService
+
+	mu      sync.Mutex
+	lookups map[resolveKey]*resolution
+}
+
+// resolveKey identifies a resolution: a reference of kind in workspace, or the
+// list of kind when ref is empty
+type resolveKey struct {
+	kind      string
+	workspace string
+	ref       string
+}
+
+// resolution is the result of a request shared by its concurrent callers,
+// done is closed once value and err are set
+type resolution struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// NewResolver creates a resolver backed by the given services
+func NewResolver(workspaces WorkspaceService, repositories RepositoryService) *Resolver {
+	return &Resolver{
+		workspaces:   workspaces,
+		repositories: repositories,
+		lookups:      map[resolveKey]*resolution{},
+	}
+}
+
+// NewResolver creates a resolver backed by the client
+func (c *Client) NewResolver() *Resolver {
+	return NewResolver(c.Workspaces, c.Repositories)
+}
+
+// once runs fn for the first caller of key and returns its result to every
+// caller. Successful results are kept, failed lookups are run again by the
+// next caller. The lock is not held while fn runs.
+func once[T any](ctx context.Context, r *Resolver, key resolveKey, fn func() (T, error)) (T, error) {
+	r.mu.Lock()
+	l, ok := r.lookups[key]
+	if !ok {
+		l = &resolution{done: make(chan struct{})}
+		r.lookups[key] = l
+	}
+	r.mu.Unlock()
+
+	if ok {
+		select {
+		case <-l.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	} else {
+		l.value, l.err = fn()
+		if l.err != nil {
+			r.mu.Lock()
+			if r.lookups[key] == l {
+				delete(r.lookups, key)
+			}
+			r.mu.Unlock()
+		}
+		close(l.done)
+	}
+
+	value, _ := l.value.(T)
+	return value, l.err
+}
+
+// listed reports whether the list of key was fetched or is being fetched
+func (r *Resolver) listed(key resolveKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.lookups[key]
+	return ok
+}
+
+// ResolveWorkspace returns the workspace whose ID or name is ref
+func (r *Resolver) ResolveWorkspace(ctx context.Context, ref string) (*Workspaces, error) {
+	return once(ctx, r, resolveKey{kind: "workspace", ref: ref}, func() (*Workspaces, error) {
+		return r.resolveWorkspace(ctx, ref)
+	})
+}
+
+func (r *Resolver) resolveWorkspace(ctx context.Context, ref string) (*Workspaces, error) {
+	listKey := resolveKey{kind: "workspace"}
+	if !r.listed(listKey) {
+		ws, err := r.workspaces.GetWorkspace(ctx, ref)
+		if err == nil {
+			return &Workspaces{Id: ws.Id, Name: ws.Name}, nil
+		}
+		if !notAnID(err) {
+			return nil, err
+		}
+	}
+
+	list, err := once(ctx, r, listKey, func() ([]Workspaces, error) {
+		list, err := r.workspaces.ListWorkspaces(WithNoCache(ctx))
+		if err != nil {
+			return nil, err
+		}
+		return *list, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Workspaces
+	for i := range list {
+		ws := &list[i]
+		if ws.Id == ref {
+			return ws, nil
+		}
+		if ws.Name == ref {
+			matches = append(matches, ws)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("workspace %q: %w", ref, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, ws := range matches {
+		ids[i] = ws.Id
+	}
+	return nil, fmt.Errorf("workspace %q: %w, use one of the IDs: %s", ref, ErrAmbiguous, strings.Join(ids, ", "))
+}
+
+// ResolveRepository returns the repository whose ID or name is ref in the
+// workspace designated by workspace, itself an ID or a name
+func (r *Resolver) ResolveRepository(ctx context.Context, workspace string, ref string) (*Workspaces, *Repositories, error) {
+	ws, err := r.ResolveWorkspace(ctx, workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo, err := once(ctx, r, resolveKey{kind: "repository", workspace: ws.Id, ref: ref}, func() (*Repositories, error) {
+		return r.resolveRepository(ctx, ws, ref)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ws, repo, nil
+}
+
+func (r *Resolver) resolveRepository(ctx context.Context, ws *Workspaces, ref string) (*Repositories, error) {
+	listKey := resolveKey{kind: "repository", workspace: ws.Id}
+	if !r.listed(listKey) {
+		repo, err := r.repositories.GetRepository(ctx, ws.Id, ref)
+		if err == nil {
+			return &Repositories{
+				Id:             repo.Id,
+				Name:           repo.Name,
+				PackageType:    repo.PackageType,
+				RepositoryType: repo.RepositoryType,
+				Status:         repo.Status,
+			}, nil
+		}
+		if !notAnID(err) {
+			return nil, err
+		}
+	}
+
+	repos, err := once(ctx, r, listKey, func() ([]Repositories, error) {
+		list, err := r.repositories.ListRepositories(WithNoCache(ctx), ws.Id)
+		if err != nil {
+			return nil, err
+		}
+		return *list, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Repositories
+	for i := range repos {
+		repo := &repos[i]
+		if repo.Id == ref {
+			return repo, nil
+		}
+		if repo.Name == ref {
+			matches = append(matches, repo)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("repository %q in workspace %q: %w", ref, ws.Name, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, repo := range matches {
+		ids[i] = repo.Id
+	}
+	return nil, fmt.Errorf("repository %q in workspace %q: %w, use one of the IDs: %s",
+		ref, ws.Name, ErrAmbiguous, strings.Join(ids, ", "))
+}
+
+// Forget drops the resolved references and the cached lists, for instance
+// after creating or deleting resources
+func (r *Resolver) Forget() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lookups = map[resolveKey]*resolution{}
+}
+
+// notAnID reports whether err, returned when fetching a reference as an ID,
+// means the reference may be a name
+func notAnID(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusBadRequest)
+}
@@ -0,0 +1,245 @@
+package repoflow_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fe80/go-repoflow/pkg/repoflow"
+	"github.com/fe80/go-repoflow/pkg/repoflow/repoflowtest"
+)
+
+// requestsTo returns the requests received by srv for method and path,
+// skipping the first skip requests
+func requestsTo(srv *repoflowtest.Server, skip int, method, path string) []repoflowtest.Request {
+	var matches []repoflowtest.Request
+	for _, req := range srv.Requests()[skip:] {
+		if req.Method == method && req.Path == path {
+			matches = append(matches, req)
+		}
+	}
+	return matches
+}
+
+func TestResolveWorkspace(t *testing.T) {
+	srv := repoflowtest.NewServer()
+	defer srv.Close()
+	team := srv.AddWorkspace("team")
+	first := srv.AddWorkspace("shared")
+	second := srv.AddWorkspace("shared")
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantID  string
+		wantErr error
+		listed  bool
+	}{
+		{"by id", team.Id, team.Id, nil, false},
+		{"by name", "team", team.Id, nil, true},
+		{"ambiguous name", "shared", "", repoflow.ErrAmbiguous, true},
+		{"ambiguous name by id", second.Id, second.Id, nil, false},
+		{"not found", "missing", "", repoflow.ErrNotFound, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skip := len(srv.Requests())
+			ws, err := srv.Client().NewResolver().ResolveWorkspace(context.Background(), tt.ref)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && ws.Id != tt.wantID {
+				t.Errorf("workspace = %+v, want %s", ws, tt.wantID)
+			}
+			if errors.Is(err, repoflow.ErrAmbiguous) && (!strings.Contains(err.Error(), first.Id) || !strings.Contains(err.Error(), second.Id)) {
+				t.Errorf("ambiguous error %q does not list the IDs", err)
+			}
+
+			lists := requestsTo(srv, skip, http.MethodGet, "/1/workspaces")
+			if listed := len(lists) > 0; listed != tt.listed {
+				t.Errorf("listed = %v, want %v", listed, tt.listed)
+			}
+			for _, req := range lists {
+				if req.Header.Get("Cache-Control") != "no-cache" {
+					t.Errorf("list sent without no-cache: %v", req.Header)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveRepository(t *testing.T) {
+	srv := repoflowtest.NewServer()
+	defer srv.Close()
+	ws := srv.AddWorkspace("team")
+	npm := srv.AddRepository(ws.Id, repoflow.Repository{Name: "npm", PackageType: "npm", RepositoryType: "local"})
+	srv.AddRepository(ws.Id, repoflow.Repository{Name: "dup", PackageType: "npm", RepositoryType: "local"})
+	srv.AddRepository(ws.Id, repoflow.Repository{Name: "dup", PackageType: "maven", RepositoryType: "local"})
+	ctx := context.Background()
+	resolver := srv.Client().NewResolver()
+
+	_, repo, err := resolver.ResolveRepository(ctx, ws.Id, npm.Id)
+	if err != nil || repo.Name != "npm" || repo.PackageType != "npm" {
+		t.Fatalf("by id = %+v, %v", repo, err)
+	}
+	if lists := requestsTo(srv, 0, http.MethodGet, "/1/workspaces/"+ws.Id+"/repositories"); len(lists) != 0 {
+		t.Errorf("resolving an ID listed the repositories")
+	}
+
+	if _, repo, err = resolver.ResolveRepository(ctx, "team", "npm"); err != nil || repo.Id != npm.Id {
+		t.Errorf("by name = %+v, %v", repo, err)
+	}
+	if _, _, err = resolver.ResolveRepository(ctx, "team", "dup"); !errors.Is(err, repoflow.ErrAmbiguous) {
+		t.Errorf("ambiguous error = %v, want ErrAmbiguous", err)
+	}
+	if _, _, err = resolver.ResolveRepository(ctx, "team", "missing"); !errors.Is(err, repoflow.ErrNotFound) {
+		t.Errorf("missing error = %v, want ErrNotFound", err)
+	}
+	if _, _, err = resolver.ResolveRepository(ctx, "other", "npm"); !errors.Is(err, repoflow.ErrNotFound) {
+		t.Errorf("missing workspace error = %v, want ErrNotFound", err)
+	}
+
+	// The list is fetched once, without cache
+	lists := requestsTo(srv, 0, http.MethodGet, "/1/workspaces/"+ws.Id+"/repositories")
+	if len(lists) != 1 {
+		t.Errorf("repository lists = %d, want 1", len(lists))
+	}
+	for _, req := range lists {
+		if req.Header.Get("Cache-Control") != "no-cache" {
+			t.Errorf("list sent without no-cache: %v", req.Header)
+		}
+	}
+}
+
+func TestResolveLookupErrors(t *testing.T) {
+	srv := repoflowtest.NewServer()
+	defer srv.Close()
+	srv.AddWorkspace("team")
+	srv.InjectFailure(repoflowtest.Failure{Method: http.MethodGet, Path: "/1/workspaces/*", Status: http.StatusInternalServerError})
+
+	_, err := srv.Client().NewResolver().ResolveWorkspace(context.Background(), "team")
+	var apiErr *repoflow.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("error = %v, want the 500", err)
+	}
+	if lists := requestsTo(srv, 0, http.MethodGet, "/1/workspaces"); len(lists) != 0 {
+		t.Errorf("listed after a server error")
+	}
+}
+
+func TestResolveBypassesCache(t *testing.T) {
+	srv := repoflowtest.NewServer()
+	defer srv.Close()
+	srv.AddWorkspace("team")
+	client := srv.Client(repoflow.WithCache(repoflow.NewCache(t.TempDir(), time.Hour)))
+	ctx := context.Background()
+
+	if _, err := client.ListWorkspaces(ctx); err != nil {
+		t.Fatal(err)
+	}
+	created := srv.AddWorkspace("created")
+
+	ws, err := client.NewResolver().ResolveWorkspace(ctx, "created")
+	if err != nil {
+		t.Fatalf("resolving a workspace missing from the cached list: %v", err)
+	}
+	if ws.Id != created.Id {
+		t.Errorf("workspace = %+v", ws)
+	}
+}
+
+func TestResolverKeepsResolvedReferences(t *testing.T) {
+	srv := repoflowtest.NewServer()
+	defer srv.Close()
+	ws := srv.AddWorkspace("team")
+	npm := srv.AddRepository(ws.Id, repoflow.Repository{Name: "npm", PackageType: "npm", RepositoryType: "local"})
+	resolver := srv.Client().NewResolver()
+	ctx := context.Background()
+
+	for range 3 {
+		if _, repo, err := resolver.ResolveRepository(ctx, ws.Id, npm.Id); err != nil || repo.Id != npm.Id {
+			t.Fatalf("ResolveRepository() = %+v, %v", repo, err)
+		}
+	}
+	if got := len(requestsTo(srv, 0, http.MethodGet, "/1/workspaces/"+ws.Id)); got != 1 {
+		t.Errorf("workspace lookups = %d, want 1", got)
+	}
+	if got := len(requestsTo(srv, 0, http.MethodGet, "/1/workspaces/"+ws.Id+"/repositories/"+npm.Id)); got != 1 {
+		t.Errorf("repository lookups = %d, want 1", got)
+	}
+
+	resolver.Forget()
+	resolver.ResolveWorkspace(ctx, ws.Id)
+	if got := len(requestsTo(srv, 0, http.MethodGet, "/1/workspaces/"+ws.Id)); got != 2 {
+		t.Errorf("workspace lookups after Forget = %d, want 2", got)
+	}
+}
+
+func TestResolverConcurrentLookups(t *testing.T) {
+	var gets, lists atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	resolver := repoflow.NewResolver(&repoflowtest.FakeWorkspaceService{
+		GetWorkspaceFunc: func(ctx context.Context, id string) (*repoflow.Workspace, error) {
+			gets.Add(1)
+			if id == "slow" {
+				close(started)
+				<-release
+			}
+			if id == "team" {
+				return nil, &repoflow.Error{StatusCode: http.StatusNotFound}
+			}
+			return &repoflow.Workspace{Id: id, Name: id}, nil
+		},
+		ListWorkspacesFunc: func(ctx context.Context) (*[]repoflow.Workspaces, error) {
+			lists.Add(1)
+			return &[]repoflow.Workspaces{{Id: "ws-1", Name: "team"}}, nil
+		},
+	}, &repoflowtest.FakeRepositoryService{})
+	ctx := context.Background()
+
+	// A slow lookup does not block the others
+	slow := make(chan error)
+	go func() {
+		_, err := resolver.ResolveWorkspace(ctx, "slow")
+		slow <- err
+	}()
+	<-started
+	done := make(chan error)
+	go func() {
+		_, err := resolver.ResolveWorkspace(ctx, "fast")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("fast lookup: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fast lookup blocked by the slow one")
+	}
+	close(release)
+	if err := <-slow; err != nil {
+		t.Errorf("slow lookup: %v", err)
+	}
+
+	// Concurrent callers of a reference share its lookup
+	gets.Store(0)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			if ws, err := resolver.ResolveWorkspace(ctx, "team"); err != nil || ws.Id != "ws-1" {
+				t.Errorf("ResolveWorkspace(team) = %+v, %v", ws, err)
+			}
+		})
+	}
+	wg.Wait()
+	if gets.Load() != 1 || lists.Load() != 1 {
+		t.Errorf("lookups by ID = %d, lists = %d, want 1 and 1", gets.Load(), lists.Load())
+	}
+}
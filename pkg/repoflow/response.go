@@ -0,0 +1,124 @@
+package repoflow
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response holds the metadata of an API response.
+// The body has already been decoded and closed.
+type Response struct {
+	*http.Response
+
+	// RequestID is the server request ID, to correlate with its logs
+	RequestID string
+	// Duration is the time until the response headers, retries included
+	Duration time.Duration
+	// Rate is the rate limit status announced by the server
+	Rate Rate
+	// Total is the number of items of a paginated listing, -1 when unknown
+	Total int
+	// NextPage is the page following a paginated listing, nil on the last page
+	NextPage *ListOptions
+}
+
+// Rate is the rate limit status of the client, fields are zero when the
+// server does not send them
+type Rate struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// newResponse extracts the metadata of resp
+func newResponse(resp *http.Response, duration time.Duration) *Response {
+	r := &Response{
+		Response:  resp,
+		RequestID: resp.Header.Get(RequestIDHeader),
+		Duration:  duration,
+		Rate:      parseRate(resp.Header),
+		Total:     -1,
+	}
+	if total, err := strconv.Atoi(resp.Header.Get("X-Total-Count")); err == nil {
+		r.Total = total
+	}
+	r.NextPage = nextPageLink(resp.Header.Get("Link"))
+	return r
+}
+
+// setPage fills the pagination hints from a paginated body
+func (r *Response) setPage(offset, limit, count, total int) {
+	if r == nil {
+		return
+	}
+	r.Total = total
+	r.NextPage = nil
+	if count > 0 && offset+count < total {
+		r.NextPage = &ListOptions{Offset: offset + count, Limit: limit}
+	}
+}
+
+// parseRate reads the X-RateLimit-* headers, or the RateLimit-* ones
+func parseRate(h http.Header) Rate {
+	var rate Rate
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		limit, err := strconv.Atoi(h.Get(prefix + "Limit"))
+		if err != nil {
+			continue
+		}
+		rate.Limit = limit
+		rate.Remaining, _ = strconv.Atoi(h.Get(prefix + "Remaining"))
+		if reset, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64); err == nil {
+			// Large values are epoch timestamps, small ones a delay in seconds
+			if reset > 1e9 {
+				rate.Reset = time.Unix(reset, 0)
+			} else {
+				rate.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+			}
+		}
+		break
+	}
+	return rate
+}
+
+// nextPageLink returns the page designated by the rel="next" link, if any
+func nextPageLink(header string) *ListOptions {
+	for _, link := range strings.Split(header, ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !hasRel(params, "next") {
+			continue
+		}
+		target = strings.TrimSpace(target)
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			return nil
+		}
+		u, err := url.Parse(target[1 : len(target)-1])
+		if err != nil {
+			return nil
+		}
+		offset, _ := strconv.Atoi(u.Query().Get("offset"))
+		limit, _ := strconv.Atoi(u.Query().Get("limit"))
+		return &ListOptions{Offset: offset, Limit: limit}
+	}
+	return nil
+}
+
+// hasRel reports whether the parameters of a link have rel among their
+// relation types, quoted or not
+func hasRel(params string, rel string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+			continue
+		}
+		for _, relType := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+			if strings.EqualFold(relType, rel) {
+				return true
+			}
+		}
+	}
+	return false
+}
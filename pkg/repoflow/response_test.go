@@ -0,0 +1,136 @@
+package repoflow
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	epoch := time.Unix(2000000000, 0)
+	tests := []struct {
+		name      string
+		header    map[string]string
+		want      Rate
+		wantDelay time.Duration
+	}{
+		{"missing", nil, Rate{}, 0},
+		{"epoch reset", map[string]string{
+			"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "42", "X-RateLimit-Reset": "2000000000",
+		}, Rate{Limit: 100, Remaining: 42, Reset: epoch}, 0},
+		{"delay reset", map[string]string{
+			"RateLimit-Limit": "100", "RateLimit-Remaining": "0", "RateLimit-Reset": "60",
+		}, Rate{Limit: 100}, time.Minute},
+		{"malformed limit", map[string]string{
+			"X-RateLimit-Limit": "many", "X-RateLimit-Remaining": "42",
+		}, Rate{}, 0},
+		{"malformed remaining and reset", map[string]string{
+			"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "some", "X-RateLimit-Reset": "soon",
+		}, Rate{Limit: 100}, 0},
+		{"prefixed headers first", map[string]string{
+			"X-RateLimit-Limit": "100", "RateLimit-Limit": "50",
+		}, Rate{Limit: 100}, 0},
+		{"standard headers when prefixed ones are malformed", map[string]string{
+			"X-RateLimit-Limit": "", "RateLimit-Limit": "50", "RateLimit-Remaining": "5",
+		}, Rate{Limit: 50, Remaining: 5}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for key, value := range tt.header {
+				h.Set(key, value)
+			}
+			got := parseRate(h)
+
+			if tt.wantDelay > 0 {
+				if delay := time.Until(got.Reset); delay <= 0 || delay > tt.wantDelay {
+					t.Errorf("reset in %s, want about %s", delay, tt.wantDelay)
+				}
+				got.Reset = time.Time{}
+			}
+			if got.Limit != tt.want.Limit || got.Remaining != tt.want.Remaining || !got.Reset.Equal(tt.want.Reset) {
+				t.Errorf("parseRate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPageLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   *ListOptions
+	}{
+		{"missing", "", nil},
+		{"next", `<https://repoflow.example.com/1/packages?offset=20&limit=10>; rel="next"`, &ListOptions{Offset: 20, Limit: 10}},
+		{"among others", `<https://x/1/p?offset=0&limit=10>; rel="prev", <https://x/1/p?offset=20&limit=10>; rel="next"`, &ListOptions{Offset: 20, Limit: 10}},
+		{"unquoted", `</1/packages?offset=5&limit=5>; rel=next`, &ListOptions{Offset: 5, Limit: 5}},
+		{"several relation types", `</1/packages?offset=5&limit=5>; title="more"; rel="next last"`, &ListOptions{Offset: 5, Limit: 5}},
+		{"only previous", `</1/packages?offset=0&limit=5>; rel="prev"`, nil},
+		{"similar relation", `</1/packages?offset=5&limit=5>; rel="nextish"`, nil},
+		{"without parameters", `</1/packages?offset=5&limit=5>`, nil},
+		{"without brackets", `/1/packages?offset=5&limit=5; rel="next"`, nil},
+		{"invalid url", `<%zz>; rel="next"`, nil},
+		{"without paging", `</1/packages>; rel="next"`, &ListOptions{}},
+		{"malformed paging", `</1/packages?offset=x&limit=10>; rel="next"`, &ListOptions{Limit: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextPageLink(tt.header)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("nextPageLink(%q) = %+v, want %+v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResponseTotal(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", -1},
+		{"42", 42},
+		{"lots", -1},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.value != "" {
+			resp.Header.Set("X-Total-Count", tt.value)
+		}
+		if got := newResponse(resp, 0).Total; got != tt.want {
+			t.Errorf("total for %q = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSetPage(t *testing.T) {
+	tests := []struct {
+		name                        string
+		offset, limit, count, total int
+		want                        *ListOptions
+	}{
+		{"first page", 0, 10, 10, 25, &ListOptions{Offset: 10, Limit: 10}},
+		{"last page", 20, 10, 5, 25, nil},
+		{"exact end", 10, 10, 10, 20, nil},
+		{"empty page", 30, 10, 0, 25, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Response{Total: -1, NextPage: &ListOptions{Offset: 99}}
+			r.setPage(tt.offset, tt.limit, tt.count, tt.total)
+			if r.Total != tt.total {
+				t.Errorf("total = %d, want %d", r.Total, tt.total)
+			}
+			if (r.NextPage == nil) != (tt.want == nil) || (r.NextPage != nil && *r.NextPage != *tt.want) {
+				t.Errorf("next page = %+v, want %+v", r.NextPage, tt.want)
+			}
+		})
+	}
+
+	var r *Response
+	r.setPage(0, 10, 10, 20)
+}
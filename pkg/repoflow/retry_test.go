@@ -0,0 +1,258 @@
+package repoflow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy retries quickly so the tests do not wait
+func fastRetryPolicy(attempts int, nonIdempotent bool) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:        attempts,
+		MinBackoff:         time.Millisecond,
+		MaxBackoff:         5 * time.Millisecond,
+		RetryNonIdempotent: nonIdempotent,
+	}
+}
+
+func TestDoRequestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		policy       *RetryPolicy
+		statuses     []int
+		wantAttempts int
+		wantStatus   int
+	}{
+		{"5xx then success", http.MethodGet, fastRetryPolicy(3, false), []int{503, 502, 200}, 3, 200},
+		{"429 then success", http.MethodGet, fastRetryPolicy(3, false), []int{429, 200}, 2, 200},
+		{"gives up after max attempts", http.MethodGet, fastRetryPolicy(3, false), []int{500, 500, 500, 500}, 3, 500},
+		{"4xx not retried", http.MethodGet, fastRetryPolicy(3, false), []int{400, 200}, 1, 400},
+		{"nil policy disables retries", http.MethodGet, nil, []int{503, 200}, 1, 503},
+		{"single attempt", http.MethodGet, fastRetryPolicy(1, false), []int{503, 200}, 1, 503},
+		{"PUT retried", http.MethodPut, fastRetryPolicy(3, false), []int{503, 200}, 2, 200},
+		{"POST not retried by default", http.MethodPost, fastRetryPolicy(3, false), []int{503, 200}, 1, 503},
+		{"POST retried on opt-in", http.MethodPost, fastRetryPolicy(3, true), []int{503, 200}, 2, 200},
+		{"PATCH retried on opt-in", http.MethodPatch, fastRetryPolicy(3, true), []int{503, 200}, 2, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1))
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses))-1])
+				io.WriteString(w, "{}")
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL, WithRetryPolicy(tt.policy))
+			err := client.DoRequest(context.Background(), tt.method, "/1/test", map[string]string{"k": "v"}, nil)
+
+			if got := int(attempts.Load()); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			var apiErr *Error
+			switch {
+			case tt.wantStatus < 400 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantStatus >= 400 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus):
+				t.Errorf("error = %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDoRequestReplaysBody(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "{}")
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithRetryPolicy(fastRetryPolicy(3, false)))
+	if err := client.DoRequest(context.Background(), http.MethodPut, "/1/test", map[string]string{"name": "npm"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != `{"name":"npm"}` {
+			t.Errorf("attempt %d body = %q", i+1, body)
+		}
+	}
+}
+
+func TestDoRequestHonorsRetryAfter(t *testing.T) {
+	var first time.Time
+	var delay time.Duration
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if first.IsZero() {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		delay = time.Since(first)
+		io.WriteString(w, "{}")
+	}))
+	defer srv.Close()
+
+	policy := fastRetryPolicy(2, false)
+	policy.MaxBackoff = 2 * time.Second
+	client := NewClient(srv.URL, WithRetryPolicy(policy))
+	if err := client.DoRequest(context.Background(), http.MethodGet, "/1/test", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if delay < 900*time.Millisecond {
+		t.Errorf("retried after %s, want about 1s", delay)
+	}
+}
+
+func TestDoRequestRetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		cancel()
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := fastRetryPolicy(3, false)
+	policy.MaxBackoff = time.Minute
+	client := NewClient(srv.URL, WithRetryPolicy(policy))
+	err := client.DoRequest(ctx, http.MethodGet, "/1/test", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		max        time.Duration
+		exact      bool
+	}{
+		{"first retry", 1, "", 100 * time.Millisecond, false},
+		{"third retry", 3, "", 400 * time.Millisecond, false},
+		{"capped", 10, "", time.Second, false},
+		{"overflow capped", 100, "", time.Second, false},
+		{"retry after seconds", 1, "0", 0, true},
+		{"retry after capped", 1, "3600", time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			for range 20 {
+				d := policy.backoff(tt.attempt, resp)
+				if d < 0 || d > tt.max || (tt.exact && d != tt.max) {
+					t.Fatalf("backoff = %s, want <= %s (exact %v)", d, tt.max, tt.exact)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got, ok := parseRetryAfter(future); !ok || got <= 0 || got > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %s, %v", future, got, ok)
+	}
+}
+
+func TestClientMethodsHonorContext(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, WithRetryPolicy(fastRetryPolicy(3, false)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.ListWorkspaces(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ListWorkspaces error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ListWorkspaces returned after %s, want the deadline", elapsed)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetRepository(canceled, "ws", "repo"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetRepository error = %v, want context.Canceled", err)
+	}
+	if _, err := client.DeleteRepositoryContent(canceled, "ws", "repo"); !errors.Is(err, context.Canceled) {
+		t.Errorf("DeleteRepositoryContent error = %v, want context.Canceled", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("requests sent with a canceled context = %d", got-1)
+	}
+}
+
+func TestClientServices(t *testing.T) {
+	client := NewClient("https://repoflow.example.com")
+	services := map[string]any{
+		"Workspaces":   client.Workspaces,
+		"Repositories": client.Repositories,
+		"Status":       client.Status,
+		"Requests":     client.Requests,
+		"Transfers":    client.Transfers,
+	}
+	for name, svc := range services {
+		if svc != any(client) {
+			t.Errorf("client.%s = %v, want the client", name, svc)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package repoflow
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrSchemaMismatch is returned in strict decoding mode when a response
+// does not match the Go type it is decoded into
+var ErrSchemaMismatch = errors.New("schema mismatch")
+
+// DecodeMode defines how responses not matching their Go type are handled
+type DecodeMode int
+
+const (
+	// DecodeLenient silently ignores unknown and missing fields
+	DecodeLenient DecodeMode = iota
+	// DecodeWarn logs a warning listing the unknown and missing fields
+	DecodeWarn
+	// DecodeStrict fails the request with a *SchemaError
+	DecodeStrict
+)
+
+// ParseDecodeMode converts "off", "warn" or "error" to its mode
+func ParseDecodeMode(mode string) (DecodeMode, error) {
+	switch mode {
+	case "", "off":
+		return DecodeLenient, nil
+	case "warn":
+		return DecodeWarn, nil
+	case "error":
+		return DecodeStrict, nil
+	}
+	return DecodeLenient, fmt.Errorf("unsupported decoding mode: %s", mode)
+}
+
+// WithStrictDecoding compares every decoded response with its Go type
+func WithStrictDecoding(mode DecodeMode) Option {
+	return func(c *Client) {
+		c.decodeMode = mode
+	}
+}
+
+// SchemaDiff lists the fields of a JSON document not matching a Go type.
+// Fields are dotted paths, "[]" standing for the items of an array.
+type SchemaDiff struct {
+	// Unknown fields are sent by the server but absent from the Go type
+	Unknown []string `json:"unknown"`
+	// Missing fields are expected by the Go type, without omitempty, but
+	// absent from the document
+	Missing []string `json:"missing"`
+}
+
+// Empty reports whether the document matches the type
+func (d *SchemaDiff) Empty() bool {
+	return len(d.Unknown) == 0 && len(d.Missing) == 0
+}
+
+// String implements fmt.Stringer
+func (d *SchemaDiff) String() string {
+	var parts []string
+	if len(d.Unknown) > 0 {
+		parts = append(parts, "unknown fields: "+strings.Join(d.Unknown, ", "))
+	}
+	if len(d.Missing) > 0 {
+		parts = append(parts, "missing fields: "+strings.Join(d.Missing, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SchemaError is returned in strict decoding mode
+type SchemaError struct {
+	Method string
+	Path   string
+	Diff   *SchemaDiff
+}
+
+// Error implements error
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s %s: %s", ErrSchemaMismatch, e.Method, e.Path, e.Diff)
+}
+
+// Is reports whether target is ErrSchemaMismatch
+func (e *SchemaError) Is(target error) bool {
+	return target == ErrSchemaMismatch
+}
+
+// CompareSchema compares the JSON document data with the Go type of v
+func CompareSchema(data []byte, v any) (*SchemaDiff, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	unknown, missing := map[string]bool{}, map[string]bool{}
+	compareValue("", doc, reflect.TypeOf(v), unknown, missing)
+
+	diff := &SchemaDiff{Unknown: sortedKeys(unknown), Missing: sortedKeys(missing)}
+	return diff, nil
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// compareValue walks doc along t, collecting the paths of the mismatches
+func compareValue(path string, doc any, t reflect.Type, unknown, missing map[string]bool) {
+	if t == nil || doc == nil {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// Types decoding themselves have their own schema
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := doc.(map[string]any)
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, value := range object {
+			field, ok := lookupField(fields, key)
+			if !ok {
+				unknown[joinPath(path, key)] = true
+				continue
+			}
+			compareValue(joinPath(path, field.name), value, field.typ, unknown, missing)
+		}
+		for _, field := range fields {
+			if field.optional {
+				continue
+			}
+			if _, ok := lookupKey(object, field.name); !ok {
+				missing[joinPath(path, field.name)] = true
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		items, ok := doc.([]any)
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			compareValue(path+"[]", item, t.Elem(), unknown, missing)
+		}
+
+	case reflect.Map:
+		object, ok := doc.(map[string]any)
+		if !ok {
+			return
+		}
+		for _, value := range object {
+			compareValue(joinPath(path, "*"), value, t.Elem(), unknown, missing)
+		}
+	}
+}
+
+// jsonField is a struct field as seen by encoding/json
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	optional bool
+}
+
+// jsonFields returns the JSON fields of t, embedded structs included
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, jsonFields(embedded)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{
+			name:     name,
+			typ:      f.Type,
+			optional: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
+	}
+	return fields
+}
+
+// lookupField finds the field of key, case-insensitively like encoding/json
+func lookupField(fields []jsonField, key string) (jsonField, bool) {
+	for _, field := range fields {
+		if field.name == key {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, key) {
+			return field, true
+		}
+	}
+	return jsonField{}, false
+}
+
+// lookupKey finds the value of a field name, case-insensitively like encoding/json
+func lookupKey(object map[string]any, name string) (any, bool) {
+	if value, ok := object[name]; ok {
+		return value, true
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,83 @@
+package repoflow
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type schemaBase struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type schemaItem struct {
+	schemaBase
+	*schemaExtra
+	Comment  string    `json:"comment,omitempty"`
+	Tags     []string  `json:"tags"`
+	Created  time.Time `json:"created"`
+	internal string
+	Ignored  string `json:"-"`
+	Untagged string
+}
+
+type schemaExtra struct {
+	Size int `json:"size,omitempty"`
+}
+
+func TestCompareSchema(t *testing.T) {
+	full := `"id":"1","name":"a","tags":[],"created":"2024-01-01T00:00:00Z","Untagged":"x"`
+
+	tests := []struct {
+		name        string
+		doc         string
+		v           any
+		wantUnknown []string
+		wantMissing []string
+	}{
+		{"omitempty not missing", `{` + full + `}`, schemaItem{}, nil, nil},
+		{"embedded fields", `{` + full + `,"size":1}`, schemaItem{}, nil, nil},
+		{"missing embedded field", `{"name":"a","tags":[],"created":"2024-01-01T00:00:00Z","Untagged":"x"}`, schemaItem{}, nil, []string{"id"}},
+		{"omitempty present", `{` + full + `,"comment":"c"}`, schemaItem{}, nil, nil},
+		{"case insensitive keys", `{"ID":"1","Name":"a","TAGS":[],"created":"2024-01-01T00:00:00Z","untagged":"x"}`, schemaItem{}, nil, nil},
+		{"unknown field", `{` + full + `,"owner":"me","internal":"x","Ignored":"y"}`, schemaItem{}, []string{"Ignored", "internal", "owner"}, nil},
+		{"array items", `[{"id":"1","name":"a","extra":1},{"id":"2"}]`, []schemaBase{}, []string{"[].extra"}, []string{"[].name"}},
+		{"pointer type", `{"id":"1"}`, &schemaBase{}, nil, []string{"name"}},
+		{"map values", `{"a":{"id":"1","name":"a","x":0}}`, map[string]schemaBase{}, []string{"*.x"}, nil},
+		{"self decoding type", `{"id":"1","name":"a","tags":[],"created":{"odd":1},"Untagged":"x"}`, schemaItem{}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := CompareSchema([]byte(tt.doc), tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(diff.Unknown, tt.wantUnknown) {
+				t.Errorf("unknown = %v, want %v", diff.Unknown, tt.wantUnknown)
+			}
+			if !slices.Equal(diff.Missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", diff.Missing, tt.wantMissing)
+			}
+			if diff.Empty() != (len(tt.wantUnknown)+len(tt.wantMissing) == 0) {
+				t.Errorf("Empty() = %v for %+v", diff.Empty(), diff)
+			}
+		})
+	}
+
+	if _, err := CompareSchema([]byte("not json"), schemaBase{}); err == nil {
+		t.Error("CompareSchema succeeded on invalid JSON")
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	err := error(&SchemaError{Method: "GET", Path: "/1/workspaces", Diff: &SchemaDiff{Unknown: []string{"owner"}}})
+	if !errors.Is(err, ErrSchemaMismatch) {
+		t.Error("SchemaError does not match ErrSchemaMismatch")
+	}
+	if got, want := err.Error(), "schema mismatch: GET /1/workspaces: unknown fields: owner"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package repoflow
+
+import (
+	"context"
+	"io"
+	"iter"
+	"time"
+)
+
+// WorkspaceService is the workspace API, implemented by *Client
+type WorkspaceService interface {
+	ListWorkspaces(ctx context.Context) (*[]Workspaces, error)
+	CreateWorkspace(ctx context.Context, opts WorkspaceOptions) (*Workspace, error)
+	GetWorkspace(ctx context.Context, id string) (*Workspace, error)
+	DeleteWorkspace(ctx context.Context, id string) (*Workspace, error)
+}
+
+// RepositoryService is the repository API, implemented by *Client
+type RepositoryService interface {
+	ListRepositories(ctx context.Context, workspace string) (*[]Repositories, error)
+	GetRepository(ctx context.Context, workspace string, id string) (*Repository, error)
+	ListRepositoryPackages(ctx context.Context, workspace string, id string, opts *ListOptions) (*RepositoryPackages, error)
+	AllRepositoryPackages(ctx context.Context, workspace string, id string, opts *ListOptions) iter.Seq2[*PackageRepository, error]
+	CreateRepository(ctx context.Context, workspace string, store string, opts any) (*Repository, error)
+	CreateLocalRepository(ctx context.Context, workspace string, opts RepositoryOptions) (*Repository, error)
+	CreateRemoteRepository(ctx context.Context, workspace string, opts RepositoryRemoteOptions) (*Repository, error)
+	CreateVirtualRepository(ctx context.Context, workspace string, opts RepositoryVirtualOptions) (*Repository, error)
+	UpdateRepository(ctx context.Context, workspace string, store string, id string, opts any) (*Repository, error)
+	UpdateLocalRepository(ctx context.Context, workspace string, id string, opts RepositoryUpdateOptions) (*Repository, error)
+	UpdateRemoteRepository(ctx context.Context, workspace string, id string, opts RepositoryRemoteUpdateOptions) (*Repository, error)
+	UpdateVirtualRepository(ctx context.Context, workspace string, id string, opts RepositoryVirtualUpdateOptions) (*Repository, error)
+	DeleteRepository(ctx context.Context, workspace string, id string) (*RepostotryDelete, error)
+	DeleteRepositoryContent(ctx context.Context, workspace string, id string) (*RepostotryDelete, error)
+}
+
+// StatusService reports the state of the server, implemented by *Client
+type StatusService interface {
+	Ping(ctx context.Context) (time.Duration, error)
+	ServerInfo(ctx context.Context) (*ServerInfo, error)
+}
+
+// RequestService sends raw API requests, implemented by *Client
+type RequestService interface {
+	DoRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error
+	DoRequestWithResponse(ctx context.Context, method, path string, body interface{}, result interface{}) (*Response, error)
+}
+
+// TransferService streams files from and to the server, implemented by *Client
+type TransferService interface {
+	Download(ctx context.Context, path string, opts *DownloadOptions) (*Download, error)
+	Upload(ctx context.Context, path string, content io.Reader, opts *UploadOptions) (*UploadResult, error)
+}
+
+var (
+	_ WorkspaceService  = (*Client)(nil)
+	_ RepositoryService = (*Client)(nil)
+	_ StatusService     = (*Client)(nil)
+	_ RequestService    = (*Client)(nil)
+	_ TransferService   = (*Client)(nil)
+)
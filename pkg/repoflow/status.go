@@ -0,0 +1,140 @@
+package repoflow
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Endpoints definitions
+const (
+	ServerInfoEndpoint  = "/1/info"
+	CurrentUserEndpoint = "/1/user"
+)
+
+// VersionHeader is the response header announcing the server version
+const VersionHeader = "X-Repoflow-Version"
+
+// ServerInfo describes the server the client talks to
+type ServerInfo struct {
+	URL     string        `json:"url"`
+	Latency time.Duration `json:"latency"`
+	// Version and Capabilities are empty when the server does not expose them
+	Version      string   `json:"version,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	// Identity is nil when the server does not expose the current user
+	Identity *Identity `json:"identity,omitempty"`
+	// TLS is nil for plain HTTP connections
+	TLS *TLSInfo `json:"tls,omitempty"`
+}
+
+// Identity is the user authenticated by the token
+type Identity struct {
+	Id       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+}
+
+// TLSInfo describes the TLS connection to the server
+type TLSInfo struct {
+	Version     string    `json:"version"`
+	CipherSuite string    `json:"cipherSuite"`
+	ServerName  string    `json:"serverName"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	NotAfter    time.Time `json:"notAfter"`
+}
+
+// serverVersion is the payload of the info endpoint
+type serverVersion struct {
+	Version      string   `json:"version"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// Ping checks the server is reachable and accepts the token, it returns the
+// round trip latency. The response cache is bypassed.
+// GET /1/workspaces
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	_, latency, err := c.ping(ctx)
+	return latency, err
+}
+
+// ServerInfo pings the server then collects its version and the current
+// identity. Endpoints missing on the server leave their fields empty, any
+// other failure is returned along with the information gathered so far.
+// The response cache is bypassed.
+// GET /1/info
+// GET /1/user
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	ctx = WithNoCache(ctx)
+	info := &ServerInfo{URL: c.BaseURL}
+
+	resp, latency, err := c.ping(ctx)
+	if err != nil {
+		return info, err
+	}
+	info.Latency = latency
+	info.Version = resp.Header.Get(VersionHeader)
+	if resp.TLS != nil {
+		info.TLS = newTLSInfo(resp.TLS)
+	}
+
+	var version serverVersion
+	switch err := c.DoRequest(ctx, http.MethodGet, ServerInfoEndpoint, nil, &version); {
+	case err == nil:
+		if version.Version != "" {
+			info.Version = version.Version
+		}
+		info.Capabilities = version.Capabilities
+	case !errors.Is(err, ErrNotFound):
+		return info, err
+	}
+
+	var identity Identity
+	switch err := c.DoRequest(ctx, http.MethodGet, CurrentUserEndpoint, nil, &identity); {
+	case err == nil:
+		info.Identity = &identity
+	case !errors.Is(err, ErrNotFound):
+		return info, err
+	}
+
+	return info, nil
+}
+
+// ping sends a lightweight authenticated request, the body is discarded
+func (c *Client) ping(ctx context.Context) (*http.Response, time.Duration, error) {
+	req, err := c.newRequest(WithNoCache(ctx), http.MethodGet, c.BaseURL+WorkspacesEndpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	start := time.Now()
+	resp, err := c.do(c.HTTPClient, req)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, latency, err
+	}
+	defer drain(resp)
+
+	if err := CheckResponse(resp); err != nil {
+		return nil, latency, err
+	}
+	return resp, latency, nil
+}
+
+func newTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		info.Subject = cert.Subject.String()
+		info.Issuer = cert.Issuer.String()
+		info.NotAfter = cert.NotAfter
+	}
+	return info
+}
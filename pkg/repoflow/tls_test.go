@@ -0,0 +1,210 @@
+package repoflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePEM writes der as a PEM block of type kind to the file name in dir
+func writePEM(t *testing.T, dir, name, kind string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newClientCertificate creates a CA and a client certificate it signed,
+// written as PEM files in dir. It returns the CA pool and the file paths.
+func newClientCertificate(t *testing.T, dir string) (pool *x509.CertPool, certFile, keyFile string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ = x509.ParseCertificate(caDER)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool = x509.NewCertPool()
+	pool.AddCert(ca)
+	return pool, writePEM(t, dir, "client.pem", "CERTIFICATE", leafDER), writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+// newTLSTestServer starts a TLS server, configured by setup before it starts
+func newTLSTestServer(t *testing.T, setup func(*tls.Config)) (*httptest.Server, string) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.TLS = &tls.Config{}
+	if setup != nil {
+		setup(srv.TLS)
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	caFile := writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	return srv, caFile
+}
+
+// tlsRequest sends a request to srv with the client TLS options
+func tlsRequest(t *testing.T, srv *httptest.Server, opts TLSOptions) error {
+	t.Helper()
+	cfg, err := opts.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := NewClient(srv.URL, WithTLSConfig(cfg), WithRetryPolicy(nil), WithLogger(logger))
+	return client.DoRequest(context.Background(), http.MethodGet, "/1/test", nil, nil)
+}
+
+func TestTLSCustomCA(t *testing.T) {
+	srv, caFile := newTLSTestServer(t, nil)
+
+	if err := tlsRequest(t, srv, TLSOptions{CAFile: caFile}); err != nil {
+		t.Errorf("request with the CA bundle: %v", err)
+	}
+	if err := tlsRequest(t, srv, TLSOptions{}); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("request without the CA bundle error = %v, want a certificate error", err)
+	}
+	if err := tlsRequest(t, srv, TLSOptions{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("insecure request: %v", err)
+	}
+}
+
+func TestTLSMutual(t *testing.T) {
+	pool, certFile, keyFile := newClientCertificate(t, t.TempDir())
+	srv, caFile := newTLSTestServer(t, func(cfg *tls.Config) {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		cfg.ClientCAs = pool
+	})
+
+	if err := tlsRequest(t, srv, TLSOptions{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}); err != nil {
+		t.Errorf("request with the client certificate: %v", err)
+	}
+	if err := tlsRequest(t, srv, TLSOptions{CAFile: caFile}); err == nil {
+		t.Error("request without client certificate succeeded")
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	srv, caFile := newTLSTestServer(t, func(cfg *tls.Config) {
+		cfg.MaxVersion = tls.VersionTLS12
+	})
+
+	if err := tlsRequest(t, srv, TLSOptions{CAFile: caFile}); err != nil {
+		t.Errorf("request with the default minimum: %v", err)
+	}
+	if err := tlsRequest(t, srv, TLSOptions{CAFile: caFile, MinVersion: tls.VersionTLS13}); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("request below the minimum version error = %v, want a version error", err)
+	}
+}
+
+func TestTLSOptionsConfig(t *testing.T) {
+	dir := t.TempDir()
+	_, certFile, keyFile := newClientCertificate(t, dir)
+	empty := filepath.Join(dir, "empty.pem")
+	os.WriteFile(empty, nil, 0o600)
+
+	tests := []struct {
+		name    string
+		opts    TLSOptions
+		wantErr string
+	}{
+		{"defaults", TLSOptions{}, ""},
+		{"missing CA bundle", TLSOptions{CAFile: filepath.Join(dir, "missing.pem")}, "failed to read CA bundle"},
+		{"empty CA bundle", TLSOptions{CAFile: empty}, "no certificate found"},
+		{"certificate without key", TLSOptions{CertFile: certFile}, "both client certificate and key"},
+		{"key mismatch", TLSOptions{CertFile: certFile, KeyFile: certFile}, "failed to load client certificate"},
+		{"client certificate", TLSOptions{CertFile: certFile, KeyFile: keyFile}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.opts.Config()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.MinVersion != tls.VersionTLS12 {
+				t.Errorf("MinVersion = %x, want TLS 1.2", cfg.MinVersion)
+			}
+		})
+	}
+}
+
+func TestTLSInsecureWarning(t *testing.T) {
+	tests := []struct {
+		insecure bool
+		want     bool
+	}{
+		{false, false},
+		{true, true},
+	}
+	for _, tt := range tests {
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, nil))
+		NewClient("https://repoflow.example.com", WithLogger(logger), WithTLSConfig(&tls.Config{InsecureSkipVerify: tt.insecure}))
+		if got := strings.Contains(logs.String(), "verification is DISABLED"); got != tt.want {
+			t.Errorf("insecure %v: warning logged = %v, want %v (%q)", tt.insecure, got, tt.want, logs.String())
+		}
+	}
+}
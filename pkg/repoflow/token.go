@@ -0,0 +1,172 @@
+package repoflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource provides the personal token sent as a bearer token
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// Refresher is implemented by token sources able to renew their token.
+// The client calls Refresh once when a request is rejected with a 401.
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// StaticTokenSource always returns the same token
+type StaticTokenSource string
+
+// Token implements TokenSource
+func (s StaticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// EnvTokenSource reads the token from an environment variable on every request
+type EnvTokenSource string
+
+// Token implements TokenSource
+func (s EnvTokenSource) Token(ctx context.Context) (string, error) {
+	token, ok := os.LookupEnv(string(s))
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", string(s))
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// FileTokenSource reads the token from a file, re-reading it when it changes
+type FileTokenSource struct {
+	Path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+// NewFileTokenSource creates a token source reading path
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{Path: path}
+}
+
+// Token implements TokenSource
+func (s *FileTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	if s.token != "" && info.ModTime().Equal(s.modTime) {
+		return s.token, nil
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	s.token = strings.TrimSpace(string(data))
+	s.modTime = info.ModTime()
+	return s.token, nil
+}
+
+// Refresh implements Refresher, the file is read again on the next request
+func (s *FileTokenSource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+	return nil
+}
+
+// CommandTokenSource runs an external command, such as a credential helper,
+// and uses its standard output as token
+type CommandTokenSource struct {
+	Command []string
+	// TTL is the lifetime of a token, 0 keeps it until a refresh
+	TTL time.Duration
+
+	mu        sync.Mutex
+	token     string
+	fetchedAt time.Time
+}
+
+// NewCommandTokenSource creates a token source running name with args
+func NewCommandTokenSource(ttl time.Duration, name string, args ...string) *CommandTokenSource {
+	return &CommandTokenSource{Command: append([]string{name}, args...), TTL: ttl}
+}
+
+// Token implements TokenSource
+func (s *CommandTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.TTL <= 0 || time.Since(s.fetchedAt) < s.TTL) {
+		return s.token, nil
+	}
+	if len(s.Command) == 0 {
+		return "", fmt.Errorf("token command is empty")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token command returned an empty token")
+	}
+	s.token = token
+	s.fetchedAt = time.Now()
+	return s.token, nil
+}
+
+// Refresh implements Refresher, the command runs again on the next request
+func (s *CommandTokenSource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+	return nil
+}
+
+// WithTokenSource sets the source of the personal token.
+// It takes precedence over WithToken.
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) {
+		c.TokenSource = source
+	}
+}
+
+// token returns the token to send
+func (c *Client) token(ctx context.Context) (string, error) {
+	if c.TokenSource != nil {
+		return c.TokenSource.Token(ctx)
+	}
+	return c.Token, nil
+}
+
+// refreshToken renews the token after a 401, it reports whether a new
+// attempt makes sense
+func (c *Client) refreshToken(ctx context.Context) bool {
+	refresher, ok := c.TokenSource.(Refresher)
+	if !ok {
+		return false
+	}
+	if err := refresher.Refresh(ctx); err != nil {
+		c.logger().Debug("Token refresh failed", "error", err)
+		return false
+	}
+	c.logger().Debug("Token refreshed after unauthorized response")
+	return true
+}
@@ -0,0 +1,178 @@
+package repoflow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source := NewFileTokenSource(path)
+	ctx := context.Background()
+
+	if token, err := source.Token(ctx); err != nil || token != "first" {
+		t.Fatalf("Token() = %q, %v, want first", token, err)
+	}
+
+	// A change keeping the modification time is only seen after a refresh
+	info, _ := os.Stat(path)
+	os.WriteFile(path, []byte("second"), 0o600)
+	os.Chtimes(path, info.ModTime(), info.ModTime())
+	if token, _ := source.Token(ctx); token != "first" {
+		t.Errorf("Token() = %q, want the cached first", token)
+	}
+	source.Refresh(ctx)
+	if token, _ := source.Token(ctx); token != "second" {
+		t.Errorf("Token() after Refresh = %q, want second", token)
+	}
+
+	// A new modification time is picked up without refresh
+	os.WriteFile(path, []byte("third"), 0o600)
+	os.Chtimes(path, info.ModTime().Add(time.Second), info.ModTime().Add(time.Second))
+	if token, _ := source.Token(ctx); token != "third" {
+		t.Errorf("Token() after change = %q, want third", token)
+	}
+
+	os.Remove(path)
+	if _, err := source.Token(ctx); err == nil {
+		t.Error("Token() succeeded without file")
+	}
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	dir := t.TempDir()
+	count := filepath.Join(dir, "count")
+	script := `echo run >> "$1"; echo "token-$(wc -l < "$1" | tr -d ' ')"`
+	ctx := context.Background()
+
+	source := NewCommandTokenSource(0, "sh", "-c", script, "sh", count)
+	for range 2 {
+		if token, err := source.Token(ctx); err != nil || token != "token-1" {
+			t.Fatalf("Token() = %q, %v, want the cached token-1", token, err)
+		}
+	}
+	source.Refresh(ctx)
+	if token, _ := source.Token(ctx); token != "token-2" {
+		t.Errorf("Token() after Refresh = %q, want token-2", token)
+	}
+
+	expiring := NewCommandTokenSource(time.Nanosecond, "sh", "-c", script, "sh", count)
+	expiring.Token(ctx)
+	if token, _ := expiring.Token(ctx); token != "token-4" {
+		t.Errorf("Token() after TTL = %q, want token-4", token)
+	}
+
+	tests := []struct {
+		name    string
+		command []string
+		want    string
+	}{
+		{"failure", []string{"sh", "-c", "echo denied >&2; exit 1"}, "denied"},
+		{"empty token", []string{"sh", "-c", "true"}, "empty token"},
+		{"no command", nil, "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &CommandTokenSource{Command: tt.command}
+			if _, err := source.Token(ctx); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Token() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// countingSource returns tokens[refreshes] and counts its calls
+type countingSource struct {
+	tokens    []string
+	err       error
+	calls     atomic.Int32
+	refreshes atomic.Int32
+}
+
+func (s *countingSource) Token(ctx context.Context) (string, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return "", s.err
+	}
+	return s.tokens[min(int(s.refreshes.Load()), len(s.tokens)-1)], nil
+}
+
+func (s *countingSource) Refresh(ctx context.Context) error {
+	s.refreshes.Add(1)
+	return nil
+}
+
+func TestTokenSourceErrorNotRetried(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	source := &countingSource{err: errors.New("helper unavailable")}
+	client := NewClient(srv.URL, WithTokenSource(source), WithRetryPolicy(fastRetryPolicy(5, false)))
+	err := client.DoRequest(context.Background(), http.MethodGet, "/1/test", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "helper unavailable") {
+		t.Errorf("error = %v, want the token source error", err)
+	}
+	if got := source.calls.Load(); got != 1 {
+		t.Errorf("token source calls = %d, want 1", got)
+	}
+	if got := hits.Load(); got != 0 {
+		t.Errorf("server hits = %d, want 0", got)
+	}
+}
+
+func TestRefreshOnUnauthorized(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        []string
+		wantHits      int32
+		wantRefreshes int32
+		wantErr       bool
+	}{
+		{"refreshed token accepted", []string{"expired", "valid"}, 2, 1, false},
+		{"refreshed token rejected", []string{"expired", "revoked"}, 2, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				if r.Header.Get("Authorization") != "Bearer valid" {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			}))
+			defer srv.Close()
+
+			source := &countingSource{tokens: tt.tokens}
+			client := NewClient(srv.URL, WithTokenSource(source), WithRetryPolicy(fastRetryPolicy(3, false)))
+			err := client.DoRequest(context.Background(), http.MethodGet, "/1/test", nil, nil)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("error = %v, want ErrUnauthorized", err)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+			if got := source.refreshes.Load(); got != tt.wantRefreshes {
+				t.Errorf("refreshes = %d, want %d", got, tt.wantRefreshes)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package repoflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+)
+
+// ProgressFunc reports the number of bytes sent, total is -1 when unknown
+type ProgressFunc func(sent, total int64)
+
+// UploadOptions defines how a file is uploaded
+type UploadOptions struct {
+	// Method is the HTTP method, PUT by default
+	Method string
+	// ContentType of the content, sent as the request or file part type,
+	// application/octet-stream by default
+	ContentType string
+	// ContentLength is the size of the content, 0 for an empty content and
+	// -1 when unknown. An unknown size is detected for files and in-memory
+	// readers, the others are sent chunked.
+	ContentLength int64
+	// Multipart sends the content as a multipart/form-data file part
+	Multipart bool
+	// FieldName is the form field of the file part, "file" by default
+	FieldName string
+	// FileName is the file name of the file part
+	FileName string
+	// Fields are extra form fields sent before the file part
+	Fields map[string]string
+	// Progress is called as the content is sent
+	Progress ProgressFunc
+	// Result receives the decoded JSON response, if any
+	Result any
+}
+
+// UploadResult describes an uploaded content
+type UploadResult struct {
+	StatusCode int    `json:"statusCode"`
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	SHA1       string `json:"sha1"`
+	MD5        string `json:"md5"`
+}
+
+// Upload streams content to path, relative to the base URL.
+// The content is never buffered in memory and, as it cannot be replayed,
+// the request is not retried. Nil options detect the content length.
+// PUT /:path
+func (c *Client) Upload(ctx context.Context, path string, content io.Reader, opts *UploadOptions) (*UploadResult, error) {
+	if opts == nil {
+		opts = &UploadOptions{ContentLength: -1}
+	}
+	method := opts.Method
+	if method == "" {
+		method = http.MethodPut
+	}
+
+	size := opts.ContentLength
+	if size < 0 {
+		size = contentLength(content)
+	}
+
+	sha256Hash, sha1Hash, md5Hash := sha256.New(), sha1.New(), md5.New()
+	checksums := io.MultiWriter(sha256Hash, sha1Hash, md5Hash)
+	progress := &progressReader{reader: io.TeeReader(content, checksums), total: size, progress: opts.Progress}
+
+	var (
+		body        io.Reader = progress
+		bodyLength            = size
+		contentType           = opts.ContentType
+	)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if opts.Multipart {
+		prefix, suffix, formType, err := multipartFrame(opts, contentType)
+		if err != nil {
+			return nil, err
+		}
+		body = io.MultiReader(bytes.NewReader(prefix), progress, bytes.NewReader(suffix))
+		contentType = formType
+		if size >= 0 {
+			bodyLength = int64(len(prefix)) + size + int64(len(suffix))
+		}
+	}
+
+	req, err := c.newRequest(ctx, method, c.resolveURL(path), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req = withStreaming(req)
+	// Without GetBody the body is sent once, chunked when its length is unknown
+	req.Body = io.NopCloser(body)
+	req.ContentLength = bodyLength
+	if bodyLength == 0 {
+		// A zero length with a body would be sent as unknown
+		req.Body = http.NoBody
+	}
+
+	resp, err := c.do(c.streamingClient(), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	if opts.Result != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(opts.Result); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return &UploadResult{
+		StatusCode: resp.StatusCode,
+		Size:       progress.sent,
+		SHA256:     hexSum(sha256Hash),
+		SHA1:       hexSum(sha1Hash),
+		MD5:        hexSum(md5Hash),
+	}, nil
+}
+
+// multipartFrame returns the multipart data surrounding the file content,
+// sent as a file part of type contentType
+func multipartFrame(opts *UploadOptions, contentType string) ([]byte, []byte, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for key, value := range opts.Fields {
+		if err := w.WriteField(key, value); err != nil {
+			return nil, nil, "", err
+		}
+	}
+
+	field := opts.FieldName
+	if field == "" {
+		field = "file"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", multipart.FileContentDisposition(field, opts.FileName))
+	header.Set("Content-Type", contentType)
+	if _, err := w.CreatePart(header); err != nil {
+		return nil, nil, "", err
+	}
+	prefix := bytes.Clone(buf.Bytes())
+
+	buf.Reset()
+	if err := w.Close(); err != nil {
+		return nil, nil, "", err
+	}
+	return prefix, bytes.Clone(buf.Bytes()), w.FormDataContentType(), nil
+}
+
+// contentLength returns the remaining size of known readers, -1 otherwise
+func contentLength(r io.Reader) int64 {
+	switch v := r.(type) {
+	case *bytes.Reader:
+		return int64(v.Len())
+	case *bytes.Buffer:
+		return int64(v.Len())
+	case *strings.Reader:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// progressReader counts the bytes read and reports them
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.sent += int64(n)
+	if r.progress != nil && n > 0 {
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+func hexSum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
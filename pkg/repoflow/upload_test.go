@@ -0,0 +1,140 @@
+package repoflow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUploadCredentialsStayOnOrigin(t *testing.T) {
+	var apiHeader, otherHeader http.Header
+	api := headerServer(t, &apiHeader)
+	other := headerServer(t, &otherHeader)
+	client := NewClient(api.URL, WithToken("secret"), WithHeader("X-Api-Key", "key"), WithRetryPolicy(nil))
+
+	tests := []struct {
+		name        string
+		path        string
+		header      *http.Header
+		credentials bool
+	}{
+		{"relative path", "/files/a", &apiHeader, true},
+		{"absolute url on the api", api.URL + "/files/a", &apiHeader, true},
+		{"absolute url elsewhere", other.URL + "/files/a", &otherHeader, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Upload(context.Background(), tt.path, strings.NewReader("content"), nil); err != nil {
+				t.Fatal(err)
+			}
+			header := *tt.header
+			if got := header.Get("Authorization") != "" || header.Get("X-Api-Key") != ""; got != tt.credentials {
+				t.Errorf("credentials sent = %v, want %v (%v)", got, tt.credentials, header)
+			}
+		})
+	}
+}
+
+func TestUpload(t *testing.T) {
+	var received, contentType string
+	var length int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		length = r.ContentLength
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+	content := "package content"
+	sum := sha256.Sum256([]byte(content))
+
+	var sent int64
+	result, err := client.Upload(context.Background(), "/files/a", strings.NewReader(content), &UploadOptions{
+		ContentLength: -1,
+		Progress:      func(n, total int64) { sent = n },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received != content || result.Size != int64(len(content)) || sent != result.Size {
+		t.Errorf("received %q, result %+v, progress %d", received, result, sent)
+	}
+	if result.SHA256 != hex.EncodeToString(sum[:]) || result.StatusCode != http.StatusCreated {
+		t.Errorf("result = %+v", result)
+	}
+
+	if length != int64(len(content)) || contentType != "application/octet-stream" {
+		t.Errorf("length = %d, content type = %q", length, contentType)
+	}
+
+	_, err = client.Upload(context.Background(), "/files/a", strings.NewReader(content), &UploadOptions{
+		ContentType:   "application/gzip",
+		ContentLength: -1,
+		Multipart:     true,
+		FileName:      "a.tgz",
+		Fields:        map[string]string{"version": "1.0.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "multipart/form-data" {
+		t.Errorf("content type = %q", contentType)
+	}
+	for _, part := range []string{`name="version"`, `filename="a.tgz"`, "Content-Type: application/gzip", content} {
+		if !strings.Contains(received, part) {
+			t.Errorf("multipart body misses %q:\n%s", part, received)
+		}
+	}
+}
+
+func TestUploadContentLength(t *testing.T) {
+	var length int64
+	var chunked bool
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		length = r.ContentLength
+		chunked = slices.Contains(r.TransferEncoding, "chunked")
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+
+	tests := []struct {
+		name        string
+		content     io.Reader
+		length      int64
+		wantLength  int64
+		wantChunked bool
+	}{
+		{"empty", strings.NewReader(""), 0, 0, false},
+		{"declared", strings.NewReader("content"), 7, 7, false},
+		{"detected", strings.NewReader("content"), -1, 7, false},
+		{"unknown", io.MultiReader(strings.NewReader("content")), -1, -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.Upload(context.Background(), "/files/a", tt.content, &UploadOptions{ContentLength: tt.length})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if length != tt.wantLength || chunked != tt.wantChunked {
+				t.Errorf("length = %d, chunked = %v, want %d and %v", length, chunked, tt.wantLength, tt.wantChunked)
+			}
+			if result.Size != int64(len(received)) {
+				t.Errorf("size = %d, received %q", result.Size, received)
+			}
+		})
+	}
+}
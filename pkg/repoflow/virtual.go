@@ -0,0 +1,182 @@
+package repoflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Errors returned when a change would break a virtual repository
+var (
+	ErrNotVirtual    = errors.New("not a virtual repository")
+	ErrInvalidMember = errors.New("invalid virtual repository member")
+)
+
+// VirtualManager changes the members of virtual repositories.
+// Every change is validated against the current repositories before being
+// sent: children share the package type of the virtual repository and the
+// upload target is a local child.
+type VirtualManager struct {
+	repositories RepositoryService
+}
+
+// NewVirtualManager creates a manager backed by the given service
+func NewVirtualManager(repositories RepositoryService) *VirtualManager {
+	return &VirtualManager{repositories: repositories}
+}
+
+// NewVirtualManager creates a manager backed by the client
+func (c *Client) NewVirtualManager() *VirtualManager {
+	return NewVirtualManager(c.Repositories)
+}
+
+// AddChildren appends children to the virtual repository id, children
+// already present keep their position
+func (v *VirtualManager) AddChildren(ctx context.Context, workspace string, id string, childIDs ...string) (*Repository, error) {
+	repo, err := v.virtual(ctx, workspace, id)
+	if err != nil {
+		return nil, err
+	}
+
+	children := childIDsOf(repo)
+	for _, childID := range childIDs {
+		if _, err := v.child(ctx, workspace, repo, childID); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(children, childID) {
+			children = append(children, childID)
+		}
+	}
+
+	return v.repositories.UpdateVirtualRepository(ctx, workspace, id, RepositoryVirtualUpdateOptions{
+		ChildRepositoryIds: &children,
+	})
+}
+
+// RemoveChildren removes children from the virtual repository id.
+// The upload target cannot be removed, it must be changed first.
+func (v *VirtualManager) RemoveChildren(ctx context.Context, workspace string, id string, childIDs ...string) (*Repository, error) {
+	repo, err := v.virtual(ctx, workspace, id)
+	if err != nil {
+		return nil, err
+	}
+
+	children := childIDsOf(repo)
+	for _, childID := range childIDs {
+		if !slices.Contains(children, childID) {
+			return nil, fmt.Errorf("%w: %s is not a child of %s", ErrInvalidMember, childID, repo.Name)
+		}
+		if childID == uploadTargetOf(repo) {
+			return nil, fmt.Errorf("%w: %s is the upload target of %s, change it first", ErrInvalidMember, childName(repo, childID), repo.Name)
+		}
+		children = slices.DeleteFunc(children, func(c string) bool { return c == childID })
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("%w: a virtual repository needs at least one child", ErrInvalidMember)
+	}
+
+	return v.repositories.UpdateVirtualRepository(ctx, workspace, id, RepositoryVirtualUpdateOptions{
+		ChildRepositoryIds: &children,
+	})
+}
+
+// Reorder sets the resolution order of the children of the virtual
+// repository id, childIDs must list every current child exactly once
+func (v *VirtualManager) Reorder(ctx context.Context, workspace string, id string, childIDs []string) (*Repository, error) {
+	repo, err := v.virtual(ctx, workspace, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := childIDsOf(repo)
+	sorted, wanted := slices.Clone(current), slices.Clone(childIDs)
+	slices.Sort(sorted)
+	slices.Sort(wanted)
+	if !slices.Equal(sorted, wanted) {
+		return nil, fmt.Errorf("%w: the order must list every child of %s exactly once", ErrInvalidMember, repo.Name)
+	}
+
+	return v.repositories.UpdateVirtualRepository(ctx, workspace, id, RepositoryVirtualUpdateOptions{
+		ChildRepositoryIds: &childIDs,
+	})
+}
+
+// SetUploadTarget sets the local child storing the uploads of the virtual
+// repository id, an empty localID removes the upload target
+func (v *VirtualManager) SetUploadTarget(ctx context.Context, workspace string, id string, localID string) (*Repository, error) {
+	repo, err := v.virtual(ctx, workspace, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if localID != "" {
+		if !slices.Contains(childIDsOf(repo), localID) {
+			return nil, fmt.Errorf("%w: the upload target %s must be a child of %s", ErrInvalidMember, localID, repo.Name)
+		}
+		local, err := v.child(ctx, workspace, repo, localID)
+		if err != nil {
+			return nil, err
+		}
+		if local.RepositoryType != "local" {
+			return nil, fmt.Errorf("%w: the upload target %s must be a local repository", ErrInvalidMember, local.Name)
+		}
+	}
+
+	return v.repositories.UpdateVirtualRepository(ctx, workspace, id, RepositoryVirtualUpdateOptions{
+		UploadLocalRepositoryId: &localID,
+	})
+}
+
+// virtual returns the repository id, checking it is virtual
+func (v *VirtualManager) virtual(ctx context.Context, workspace string, id string) (*Repository, error) {
+	repo, err := v.repositories.GetRepository(ctx, workspace, id)
+	if err != nil {
+		return nil, err
+	}
+	if repo.RepositoryType != "virtual" {
+		return nil, fmt.Errorf("repository %s: %w", repo.Name, ErrNotVirtual)
+	}
+	return repo, nil
+}
+
+// child returns the repository childID, checking it can be a child of repo
+func (v *VirtualManager) child(ctx context.Context, workspace string, repo *Repository, childID string) (*Repository, error) {
+	if childID == repo.Id {
+		return nil, fmt.Errorf("%w: %s cannot be its own child", ErrInvalidMember, repo.Name)
+	}
+	child, err := v.repositories.GetRepository(ctx, workspace, childID)
+	if err != nil {
+		return nil, err
+	}
+	if child.PackageType != repo.PackageType {
+		return nil, fmt.Errorf("%w: %s stores %s packages, %s stores %s packages",
+			ErrInvalidMember, child.Name, child.PackageType, repo.Name, repo.PackageType)
+	}
+	return child, nil
+}
+
+func childIDsOf(repo *Repository) []string {
+	ids := make([]string, 0, len(repo.ChildRepositories))
+	for _, child := range repo.ChildRepositories {
+		ids = append(ids, child.Id)
+	}
+	return ids
+}
+
+// childName returns the name of the child childID of repo, or its ID
+func childName(repo *Repository, childID string) string {
+	for _, child := range repo.ChildRepositories {
+		if child.Id == childID && child.Name != "" {
+			return child.Name
+		}
+	}
+	return childID
+}
+
+func uploadTargetOf(repo *Repository) string {
+	if repo.UploadLocalRepositoryId != nil {
+		return *repo.UploadLocalRepositoryId
+	}
+	return repo.UploadTargetLocalRepository.Id
+}
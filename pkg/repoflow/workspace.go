@@ -1,6 +1,7 @@
 package repoflow
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -39,34 +40,58 @@ type WorkspaceOptions struct {
 
 // ListWorkspaces retrieves all available workspaces
 // GET /1/workspaces
-func (c *Client) ListWorkspaces() (*[]Workspaces, error) {
+func (c *Client) ListWorkspaces(ctx context.Context) (*[]Workspaces, error) {
+	ws, _, err := c.ListWorkspacesWithResponse(ctx)
+	return ws, err
+}
+
+// ListWorkspacesWithResponse is ListWorkspaces also returning the response metadata
+func (c *Client) ListWorkspacesWithResponse(ctx context.Context) (*[]Workspaces, *Response, error) {
 	var ws []Workspaces
-	err := c.DoRequest(http.MethodGet, WorkspacesEndpoint, nil, &ws)
-	return &ws, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodGet, WorkspacesEndpoint, nil, &ws)
+	return &ws, resp, err
 }
 
 // CreateWorkspace creates a new workspace with the given options
 // POST /1/workspaces
-func (c *Client) CreateWorkspace(opts WorkspaceOptions) (*Workspace, error) {
+func (c *Client) CreateWorkspace(ctx context.Context, opts WorkspaceOptions) (*Workspace, error) {
+	ws, _, err := c.CreateWorkspaceWithResponse(ctx, opts)
+	return ws, err
+}
+
+// CreateWorkspaceWithResponse is CreateWorkspace also returning the response metadata
+func (c *Client) CreateWorkspaceWithResponse(ctx context.Context, opts WorkspaceOptions) (*Workspace, *Response, error) {
 	var ws Workspace
-	err := c.DoRequest(http.MethodPost, WorkspacesEndpoint, opts, &ws)
-	return &ws, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodPost, WorkspacesEndpoint, opts, &ws)
+	return &ws, resp, err
 }
 
 // GetWorkspace retrieves metadata for a specific workspace
 // GET /1/workspaces/:id
-func (c *Client) GetWorkspace(id string) (*Workspace, error) {
+func (c *Client) GetWorkspace(ctx context.Context, id string) (*Workspace, error) {
+	ws, _, err := c.GetWorkspaceWithResponse(ctx, id)
+	return ws, err
+}
+
+// GetWorkspaceWithResponse is GetWorkspace also returning the response metadata
+func (c *Client) GetWorkspaceWithResponse(ctx context.Context, id string) (*Workspace, *Response, error) {
 	var ws Workspace
 	endpoint := fmt.Sprintf("%s/%s", WorkspacesEndpoint, id)
-	err := c.DoRequest(http.MethodGet, endpoint, nil, &ws)
-	return &ws, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodGet, endpoint, nil, &ws)
+	return &ws, resp, err
 }
 
 // DeleteWorkspace removes a workspace by its ID
 // DELETE /1/workspaces/:id
-func (c *Client) DeleteWorkspace(id string) (*Workspace, error) {
+func (c *Client) DeleteWorkspace(ctx context.Context, id string) (*Workspace, error) {
+	ws, _, err := c.DeleteWorkspaceWithResponse(ctx, id)
+	return ws, err
+}
+
+// DeleteWorkspaceWithResponse is DeleteWorkspace also returning the response metadata
+func (c *Client) DeleteWorkspaceWithResponse(ctx context.Context, id string) (*Workspace, *Response, error) {
 	var ws Workspace
 	endpoint := fmt.Sprintf("%s/%s", WorkspacesEndpoint, id)
-	err := c.DoRequest(http.MethodDelete, endpoint, nil, &ws)
-	return &ws, err
+	resp, err := c.DoRequestWithResponse(ctx, http.MethodDelete, endpoint, nil, &ws)
+	return &ws, resp, err
 }